@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -25,6 +27,10 @@ import (
 
 	"github.com/eclipse/codewind-operator/pkg/apis"
 	"github.com/eclipse/codewind-operator/pkg/controller"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/platform"
+	"github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/eclipse/codewind-operator/pkg/webhook"
 	"github.com/eclipse/codewind-operator/version"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -35,7 +41,12 @@ import (
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -71,11 +82,40 @@ func main() {
 	logf.SetLogger(zap.Logger())
 	printVersion()
 
-	namespace, err := k8sutil.GetWatchNamespace()
+	// Watch a single namespace, a comma separated list of namespaces, or the whole cluster when WATCH_NAMESPACE is empty
+	namespaces, err := util.GetWatchNamespaces()
 	if err != nil {
 		log.Error(err, "Failed to get watch namespace")
 		os.Exit(1)
 	}
+	namespaceSelector, err := util.GetWatchNamespaceSelector()
+	if err != nil {
+		log.Error(err, "Invalid watch namespace selector", "Selector", os.Getenv(util.WatchNamespaceSelectorEnvVar))
+		os.Exit(1)
+	}
+	if namespaceSelector != nil && len(namespaces) > 0 {
+		log.Error(errors.New("a watch namespace selector needs an empty WATCH_NAMESPACE"), "The watch namespace selector only applies when watching the whole cluster")
+		os.Exit(1)
+	}
+	// The operator config map and CA are read from the operator namespace, which must always be watched
+	if len(namespaces) > 0 && !containsNamespace(namespaces, util.GetOperatorNamespace()) {
+		namespaces = append(namespaces, util.GetOperatorNamespace())
+	}
+	managerOptions := manager.Options{
+		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Port:               defaults.WebhookPort,
+		CertDir:            defaults.WebhookCertDir,
+	}
+	switch len(namespaces) {
+	case 0:
+		log.Info("Watching all namespaces", "Selector", namespaceSelector)
+	case 1:
+		log.Info("Watching a single namespace", "Namespace", namespaces[0])
+		managerOptions.Namespace = namespaces[0]
+	default:
+		log.Info("Watching several namespaces", "Namespaces", namespaces)
+		managerOptions.NewCache = util.MultiNamespaceCacheBuilder(namespaces)
+	}
 
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
@@ -84,6 +124,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Detect the platform once, its profile supplies the storage, ingress and security defaults of the controllers
+	profile, err := detectPlatform(cfg)
+	if err != nil {
+		log.Error(err, "Unable to detect the platform, set the platform key of the operator config map to skip detection")
+		os.Exit(1)
+	}
+	log.Info("Detected the platform", "Platform", profile.Name, "Description", profile.Description)
+
 	ctx := context.TODO()
 	// Become the leader before proceeding
 	err = leader.Become(ctx, "codewind-operator-lock")
@@ -93,10 +141,7 @@ func main() {
 	}
 
 	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
-		Namespace:          namespace,
-		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
-	})
+	mgr, err := manager.New(cfg, managerOptions)
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
@@ -116,14 +161,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Adding apiextensions so that the CA of the conversion webhook can be set on the Codewind CRD
+	if err := apiextensionsv1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
+	// Setup the admission webhooks
+	if err := addWebhooks(cfg, mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	// Add the Metrics Service
-	addMetrics(ctx, cfg, namespace)
+	addMetrics(ctx, cfg, namespaces)
 
 	log.Info("Starting the Cmd.")
 
@@ -134,10 +191,64 @@ func main() {
 	}
 }
 
+// detectPlatform : detects the platform, retrying with a backoff as the profile is not detected again until the
+// operator restarts. Falling back to vanilla Kubernetes would create ingresses instead of routes on OpenShift, so the
+// last error is returned unless the operator config map names the platform
+func detectPlatform(cfg *rest.Config) (*platform.Profile, error) {
+	var profile *platform.Profile
+	var detectErr error
+	backoff := wait.Backoff{Duration: 2 * time.Second, Factor: 2, Steps: 5}
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		profile, detectErr = platform.Detect(cfg)
+		if detectErr != nil {
+			log.Info("Unable to detect the platform, retrying", "Error", detectErr.Error())
+		}
+		return detectErr == nil, nil
+	})
+	if err == nil {
+		return profile, nil
+	}
+
+	// The cache of the manager is not started yet so the config map is read with a direct client
+	directClient, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, detectErr
+	}
+	operatorConfig, err := util.ReadConfigMapData(directClient, defaults.OperatorConfigMapName, util.GetOperatorNamespace())
+	if err != nil || strings.TrimSpace(operatorConfig[platform.ConfigMapKey]) == "" {
+		return nil, detectErr
+	}
+	log.Error(detectErr, "Unable to detect the platform, using the platform of the operator config map", "Platform", operatorConfig[platform.ConfigMapKey])
+	return platform.Current(operatorConfig)
+}
+
+// addWebhooks writes the serving certificate of the admission webhooks and registers them with the manager. The
+// webhooks are not served when running locally as the API server cannot reach the operator
+func addWebhooks(cfg *rest.Config, mgr manager.Manager) error {
+	_, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		if errors.Is(err, k8sutil.ErrRunLocal) {
+			log.Info("Skipping the admission webhooks; not running in a cluster.")
+			return nil
+		}
+		return err
+	}
+	// The cache of the manager is not started yet so the certificate is set up with a direct client
+	directClient, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return err
+	}
+	err = webhook.ConfigureServingCertificate(directClient, defaults.WebhookCertDir)
+	if err != nil {
+		return err
+	}
+	return webhook.AddToManager(mgr)
+}
+
 // addMetrics will create the Services and Service Monitors to allow the operator to export the metrics by using
 // the Prometheus operator
-func addMetrics(ctx context.Context, cfg *rest.Config, namespace string) {
-	if err := serveCRMetrics(cfg); err != nil {
+func addMetrics(ctx context.Context, cfg *rest.Config, namespaces []string) {
+	if err := serveCRMetrics(cfg, namespaces); err != nil {
 		if errors.Is(err, k8sutil.ErrRunLocal) {
 			log.Info("Skipping CR metrics server creation; not running in a cluster.")
 			return
@@ -160,7 +271,7 @@ func addMetrics(ctx context.Context, cfg *rest.Config, namespace string) {
 	// CreateServiceMonitors will automatically create the prometheus-operator and ServiceMonitor resources
 	// necessary to configure Prometheus to scrape metrics from this operator.
 	services := []*v1.Service{service}
-	_, err = metrics.CreateServiceMonitors(cfg, namespace, services)
+	_, err = metrics.CreateServiceMonitors(cfg, util.GetOperatorNamespace(), services)
 	if err != nil {
 		log.Info("Could not create ServiceMonitor object", "error", err.Error())
 		// If this operator is deployed to a cluster without the prometheus-operator running, it will return
@@ -171,21 +282,33 @@ func addMetrics(ctx context.Context, cfg *rest.Config, namespace string) {
 	}
 }
 
-// serveCRMetrics gets the Operator/CustomResource GVKs and generates metrics based on those types.
-// It serves those metrics on "http://metricsHost:operatorMetricsPort".
-func serveCRMetrics(cfg *rest.Config) error {
+// serveCRMetrics gets the Operator/CustomResource GVKs and generates metrics based on those types in the watched
+// namespaces. It serves those metrics on "http://metricsHost:operatorMetricsPort".
+func serveCRMetrics(cfg *rest.Config, namespaces []string) error {
 	// Below function returns filtered operator/CustomResource specific GVKs.
-	filteredGVK, err := k8sutil.GetGVKsFromAddToScheme(apis.AddToScheme)
+	operatorGVKs, err := k8sutil.GetGVKsFromAddToScheme(apis.AddToScheme)
 	if err != nil {
 		return err
 	}
-	// Get the namespace the operator is currently deployed in.
-	operatorNs, err := k8sutil.GetOperatorNamespace()
+	// Kinds served at several API versions are reported once, at their first registered version.
+	filteredGVK := []schema.GroupVersionKind{}
+	reportedKinds := map[string]bool{}
+	for _, gvk := range operatorGVKs {
+		if !reportedKinds[gvk.Kind] {
+			reportedKinds[gvk.Kind] = true
+			filteredGVK = append(filteredGVK, gvk)
+		}
+	}
+	// Make sure the operator runs in a cluster, the metrics are not served when running locally.
+	_, err = k8sutil.GetOperatorNamespace()
 	if err != nil {
 		return err
 	}
-	// To generate metrics in other namespaces, add the values below.
-	ns := []string{operatorNs}
+	// Generate metrics in every watched namespace, or across the cluster when all namespaces are watched.
+	ns := namespaces
+	if len(ns) == 0 {
+		ns = []string{metav1.NamespaceAll}
+	}
 	// Generate and serve custom resource specific metrics.
 	err = kubemetrics.GenerateAndServeCRMetrics(cfg, ns, filteredGVK, metricsHost, operatorMetricsPort)
 	if err != nil {
@@ -193,3 +316,13 @@ func serveCRMetrics(cfg *rest.Config) error {
 	}
 	return nil
 }
+
+// containsNamespace : returns true when namespace is in the list
+func containsNamespace(namespaces []string, namespace string) bool {
+	for _, item := range namespaces {
+		if item == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -92,10 +92,14 @@ function installOperator() {
     echo "Installing Custom Resource Definitions (CRD) for Openshift 3.11:"
     kubectl apply -f codewind.eclipse.org_keycloaks_crd-oc311.yaml
     kubectl apply -f codewind.eclipse.org_codewinds_crd-oc311.yaml
+    kubectl apply -f codewind.eclipse.org_keycloakbackups_crd-oc311.yaml
+    kubectl apply -f codewind.eclipse.org_keycloakrestores_crd-oc311.yaml
     else
     echo "Installing Custom Resource Definitions (CRD):"
     kubectl apply -f codewind.eclipse.org_keycloaks_crd.yaml
     kubectl apply -f codewind.eclipse.org_codewinds_crd.yaml
+    kubectl apply -f codewind.eclipse.org_keycloakbackups_crd.yaml
+    kubectl apply -f codewind.eclipse.org_keycloakrestores_crd.yaml
     fi
 
     cd ..
@@ -104,14 +108,24 @@ function installOperator() {
 
     head -n17 codewind-configmap.yaml > custom-codewind-configmap.yaml
     echo "  ingressDomain: "$FLG_INGRESS_DOMAIN >> custom-codewind-configmap.yaml
-    tail -n3 codewind-configmap.yaml >> custom-codewind-configmap.yaml
+    tail -n +19 codewind-configmap.yaml >> custom-codewind-configmap.yaml
 
     kubectl apply -f custom-codewind-configmap.yaml
     rm -f custom-codewind-configmap.yaml
 
+    # Openshift 3.11 cannot convert between API versions or match webhooks on equivalent versions
+    if [[ $FLG_OC311 != true ]]
+    then
+    echo "Deploying Codewind operator admission webhooks:"
+    kubectl apply -f webhook.yaml
+    fi
+
     echo "Deploying Codewind operator:"
     kubectl apply -f operator.yaml
 
+    echo "Waiting for the Codewind operator to start:"
+    kubectl rollout status deployment/codewind-operator -n $FLG_NAMESPACE
+
     cd crds
 
     echo "Requesting a new Keycloak service"
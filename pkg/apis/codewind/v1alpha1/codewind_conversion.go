@@ -0,0 +1,277 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/eclipse/codewind-operator/pkg/apis/codewind/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// emptyFieldsAnnotation : lists the spec fields of a v1alpha1 Codewind that are set but empty. The hub version cannot
+// tell them apart from unset fields, so they are recorded on the hub object to survive a round trip
+const emptyFieldsAnnotation = "codewind.eclipse.org/v1alpha1-empty-fields"
+
+// ConvertTo : converts this Codewind to the v1beta1 hub version
+func (src *Codewind) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Codewind)
+	dst.ObjectMeta = src.ObjectMeta
+
+	emptyFields := []string{}
+	if src.Spec.Storage != nil && src.Spec.Storage.StorageClassName == "" && src.Spec.Storage.AccessModes == nil {
+		emptyFields = append(emptyFields, "storage")
+	}
+	if src.Spec.Images != nil && *src.Spec.Images == (CodewindImages{}) {
+		emptyFields = append(emptyFields, "images")
+	}
+	if src.Spec.PodTemplates != nil && *src.Spec.PodTemplates == (CodewindPodTemplates{}) {
+		emptyFields = append(emptyFields, "podTemplates")
+	}
+	setAnnotation(&dst.ObjectMeta, emptyFieldsAnnotation, strings.Join(emptyFields, ","))
+
+	dst.Spec = v1beta1.CodewindSpec{
+		Auth: v1beta1.CodewindAuth{
+			Username:            src.Spec.Username,
+			KeycloakDeployment:  src.Spec.KeycloakDeployment,
+			KeycloakNamespace:   src.Spec.KeycloakNamespace,
+			RotateCredentialsAt: src.Spec.RotateCredentialsAt,
+		},
+		LogLevel:    src.Spec.LogLevel,
+		Version:     src.Spec.Version,
+		Suspended:   src.Spec.Suspended,
+		IdleTimeout: src.Spec.IdleTimeout,
+	}
+	if src.Spec.ExternalAuth != nil {
+		externalAuth := v1beta1.ExternalAuthSpec(*src.Spec.ExternalAuth)
+		dst.Spec.Auth.External = &externalAuth
+	}
+	if src.Spec.StorageSize != "" || src.Spec.Storage != nil {
+		dst.Spec.Storage = &v1beta1.CodewindStorage{Size: src.Spec.StorageSize}
+		if src.Spec.Storage != nil {
+			dst.Spec.Storage.StorageClassName = src.Spec.Storage.StorageClassName
+			dst.Spec.Storage.AccessModes = src.Spec.Storage.AccessModes
+		}
+	}
+	if src.Spec.IssuerRef != nil {
+		issuerRef := v1beta1.IssuerReference(*src.Spec.IssuerRef)
+		dst.Spec.Networking = &v1beta1.CodewindNetworking{IssuerRef: &issuerRef}
+	}
+
+	var images CodewindImages
+	if src.Spec.Images != nil {
+		images = *src.Spec.Images
+	}
+	var podTemplates CodewindPodTemplates
+	if src.Spec.PodTemplates != nil {
+		podTemplates = *src.Spec.PodTemplates
+	}
+	components := v1beta1.CodewindComponents{
+		ImagePullPolicy:  src.Spec.ImagePullPolicy,
+		ImagePullSecrets: src.Spec.ImagePullSecrets,
+		PFE:              componentToHub(images.PFE, podTemplates.PFE),
+		Performance:      componentToHub(images.Performance, podTemplates.Performance),
+		Gatekeeper:       componentToHub(images.Gatekeeper, podTemplates.Gatekeeper),
+	}
+	if components.ImagePullPolicy != "" || components.ImagePullSecrets != nil || components.PFE != nil || components.Performance != nil || components.Gatekeeper != nil {
+		dst.Spec.Components = &components
+	}
+
+	dst.Status = v1beta1.CodewindStatus{
+		AuthURL:              src.Status.AuthURL,
+		AccessURL:            src.Status.AccessURL,
+		KeycloakStatus:       src.Status.KeycloakStatus,
+		Phase:                v1beta1.CodewindPhase(src.Status.Phase),
+		ObservedGeneration:   src.Status.ObservedGeneration,
+		Images:               v1beta1.CodewindStatusImages(src.Status.Images),
+		CurrentVersion:       src.Status.CurrentVersion,
+		UpgradeState:         v1beta1.UpgradeState(src.Status.UpgradeState),
+		LastActivityTime:     src.Status.LastActivityTime,
+		CredentialsRotatedAt: src.Status.CredentialsRotatedAt,
+		CertificateNotAfter:  src.Status.CertificateNotAfter,
+	}
+	if src.Status.Storage != nil {
+		storage := v1beta1.StorageStatus{
+			StorageClassName: src.Status.Storage.StorageClassName,
+			AccessModes:      src.Status.Storage.AccessModes,
+			RequestedSize:    src.Status.Storage.RequestedSize,
+			Capacity:         src.Status.Storage.Capacity,
+			ResizeState:      v1beta1.StorageResizeState(src.Status.Storage.ResizeState),
+			Message:          src.Status.Storage.Message,
+		}
+		dst.Status.Storage = &storage
+	}
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, v1beta1.CodewindCondition{
+			Type:               v1beta1.CodewindConditionType(condition.Type),
+			Status:             condition.Status,
+			ObservedGeneration: condition.ObservedGeneration,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return nil
+}
+
+// ConvertFrom : converts a Codewind of the v1beta1 hub version to this version
+func (dst *Codewind) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Codewind)
+	dst.ObjectMeta = src.ObjectMeta
+	emptyFields := map[string]bool{}
+	for _, field := range strings.Split(src.GetAnnotations()[emptyFieldsAnnotation], ",") {
+		emptyFields[field] = true
+	}
+	setAnnotation(&dst.ObjectMeta, emptyFieldsAnnotation, "")
+
+	dst.Spec = CodewindSpec{
+		KeycloakDeployment:  src.Spec.Auth.KeycloakDeployment,
+		KeycloakNamespace:   src.Spec.Auth.KeycloakNamespace,
+		Username:            src.Spec.Auth.Username,
+		RotateCredentialsAt: src.Spec.Auth.RotateCredentialsAt,
+		LogLevel:            src.Spec.LogLevel,
+		Version:             src.Spec.Version,
+		Suspended:           src.Spec.Suspended,
+		IdleTimeout:         src.Spec.IdleTimeout,
+	}
+	if src.Spec.Auth.External != nil {
+		externalAuth := ExternalAuthSpec(*src.Spec.Auth.External)
+		dst.Spec.ExternalAuth = &externalAuth
+	}
+	if src.Spec.Storage != nil {
+		dst.Spec.StorageSize = src.Spec.Storage.Size
+		if src.Spec.Storage.StorageClassName != "" || src.Spec.Storage.AccessModes != nil || emptyFields["storage"] {
+			dst.Spec.Storage = &StorageSpec{
+				StorageClassName: src.Spec.Storage.StorageClassName,
+				AccessModes:      src.Spec.Storage.AccessModes,
+			}
+		}
+	}
+	if src.Spec.Networking != nil && src.Spec.Networking.IssuerRef != nil {
+		issuerRef := IssuerReference(*src.Spec.Networking.IssuerRef)
+		dst.Spec.IssuerRef = &issuerRef
+	}
+
+	if components := src.Spec.Components; components != nil {
+		dst.Spec.ImagePullPolicy = components.ImagePullPolicy
+		dst.Spec.ImagePullSecrets = components.ImagePullSecrets
+		images := CodewindImages{}
+		podTemplates := CodewindPodTemplates{}
+		images.PFE, podTemplates.PFE = componentFromHub(components.PFE)
+		images.Performance, podTemplates.Performance = componentFromHub(components.Performance)
+		images.Gatekeeper, podTemplates.Gatekeeper = componentFromHub(components.Gatekeeper)
+		if images.PFE != nil || images.Performance != nil || images.Gatekeeper != nil {
+			dst.Spec.Images = &images
+		}
+		if podTemplates.PFE != nil || podTemplates.Performance != nil || podTemplates.Gatekeeper != nil {
+			dst.Spec.PodTemplates = &podTemplates
+		}
+	}
+	if dst.Spec.Images == nil && emptyFields["images"] {
+		dst.Spec.Images = &CodewindImages{}
+	}
+	if dst.Spec.PodTemplates == nil && emptyFields["podTemplates"] {
+		dst.Spec.PodTemplates = &CodewindPodTemplates{}
+	}
+
+	dst.Status = CodewindStatus{
+		AuthURL:              src.Status.AuthURL,
+		AccessURL:            src.Status.AccessURL,
+		KeycloakStatus:       src.Status.KeycloakStatus,
+		Phase:                CodewindPhase(src.Status.Phase),
+		ObservedGeneration:   src.Status.ObservedGeneration,
+		Images:               CodewindStatusImages(src.Status.Images),
+		CurrentVersion:       src.Status.CurrentVersion,
+		UpgradeState:         UpgradeState(src.Status.UpgradeState),
+		LastActivityTime:     src.Status.LastActivityTime,
+		CredentialsRotatedAt: src.Status.CredentialsRotatedAt,
+		CertificateNotAfter:  src.Status.CertificateNotAfter,
+	}
+	if src.Status.Storage != nil {
+		storage := StorageStatus{
+			StorageClassName: src.Status.Storage.StorageClassName,
+			AccessModes:      src.Status.Storage.AccessModes,
+			RequestedSize:    src.Status.Storage.RequestedSize,
+			Capacity:         src.Status.Storage.Capacity,
+			ResizeState:      StorageResizeState(src.Status.Storage.ResizeState),
+			Message:          src.Status.Storage.Message,
+		}
+		dst.Status.Storage = &storage
+	}
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, CodewindCondition{
+			Type:               CodewindConditionType(condition.Type),
+			Status:             condition.Status,
+			ObservedGeneration: condition.ObservedGeneration,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return nil
+}
+
+// componentToHub : combines the image and pod template overrides of a component, nil when neither is set
+func componentToHub(image *ImageSpec, podTemplate *PodTemplateOverrides) *v1beta1.CodewindComponent {
+	if image == nil && podTemplate == nil {
+		return nil
+	}
+	component := &v1beta1.CodewindComponent{}
+	if image != nil {
+		hubImage := v1beta1.ImageSpec(*image)
+		component.Image = &hubImage
+	}
+	if podTemplate != nil {
+		hubPodTemplate := v1beta1.PodTemplateOverrides(*podTemplate)
+		component.PodTemplate = &hubPodTemplate
+	}
+	return component
+}
+
+// componentFromHub : splits the settings of a component into its image and pod template overrides
+func componentFromHub(component *v1beta1.CodewindComponent) (*ImageSpec, *PodTemplateOverrides) {
+	if component == nil {
+		return nil, nil
+	}
+	var image *ImageSpec
+	if component.Image != nil {
+		spokeImage := ImageSpec(*component.Image)
+		image = &spokeImage
+	}
+	var podTemplate *PodTemplateOverrides
+	if component.PodTemplate != nil {
+		spokePodTemplate := PodTemplateOverrides(*component.PodTemplate)
+		podTemplate = &spokePodTemplate
+	}
+	return image, podTemplate
+}
+
+// setAnnotation : sets an annotation, or removes it when the value is empty, without changing the annotations of the
+// object the metadata was copied from
+func setAnnotation(meta *metav1.ObjectMeta, key string, value string) {
+	if _, ok := meta.Annotations[key]; !ok && value == "" {
+		return
+	}
+	annotations := make(map[string]string, len(meta.Annotations)+1)
+	for k, v := range meta.Annotations {
+		annotations[k] = v
+	}
+	if value == "" {
+		delete(annotations, key)
+	} else {
+		annotations[key] = value
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	meta.Annotations = annotations
+}
@@ -0,0 +1,172 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eclipse/codewind-operator/pkg/apis/codewind/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// populatedCodewind : returns a Codewind with every spec and status field set
+func populatedCodewind() *Codewind {
+	at := metav1.NewTime(time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC))
+	podTemplate := func(zone string) *PodTemplateOverrides {
+		return &PodTemplateOverrides{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			NodeSelector: map[string]string{"zone": zone},
+			Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "codewind", Effect: corev1.TaintEffectNoSchedule}},
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+						Weight:          100,
+						PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"},
+					}},
+				},
+			},
+		}
+	}
+	return &Codewind{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "jane1",
+			Namespace:   "codewind",
+			Generation:  3,
+			Labels:      map[string]string{"team": "tools"},
+			Annotations: map[string]string{"codewindWorkspace": "k8x1y2z3"},
+			Finalizers:  []string{"codewinds.codewind.eclipse.org"},
+		},
+		Spec: CodewindSpec{
+			KeycloakDeployment: "devex",
+			KeycloakNamespace:  "keycloak",
+			ExternalAuth: &ExternalAuthSpec{
+				URL:               "https://keycloak.example.com",
+				Realm:             "codewind",
+				CredentialsSecret: "keycloak-admin",
+				CABundleConfigMap: "keycloak-ca",
+			},
+			Username:    "jane",
+			StorageSize: "10Gi",
+			Storage: &StorageSpec{
+				StorageClassName: "fast",
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			},
+			LogLevel:            "debug",
+			Version:             "0.13.0",
+			Suspended:           true,
+			IdleTimeout:         &metav1.Duration{Duration: 8 * time.Hour},
+			RotateCredentialsAt: &at,
+			Images: &CodewindImages{
+				PFE:         &ImageSpec{Repository: "registry.example.com/codewind-pfe-amd64", Tag: "0.13.0"},
+				Performance: &ImageSpec{Repository: "registry.example.com/codewind-performance-amd64"},
+				Gatekeeper:  &ImageSpec{Digest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+			},
+			ImagePullPolicy:  corev1.PullIfNotPresent,
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-credentials"}},
+			PodTemplates: &CodewindPodTemplates{
+				PFE:         podTemplate("a"),
+				Performance: podTemplate("b"),
+				Gatekeeper:  podTemplate("c"),
+			},
+			IssuerRef: &IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+		},
+		Status: CodewindStatus{
+			AuthURL:            "https://codewind-keycloak-k8x1y2z3.codewind.apps.example.com",
+			AccessURL:          "https://codewind-gatekeeper-k8x1y2z3.codewind.apps.example.com",
+			KeycloakStatus:     "Complete",
+			Phase:              CodewindPhaseSuspended,
+			ObservedGeneration: 3,
+			Conditions: []CodewindCondition{{
+				Type:               CodewindConditionReady,
+				Status:             corev1.ConditionFalse,
+				ObservedGeneration: 3,
+				LastTransitionTime: at,
+				Reason:             "Suspended",
+				Message:            "The instance is suspended",
+			}},
+			Images: CodewindStatusImages{
+				PFE:         "registry.example.com/codewind-pfe-amd64:0.13.0",
+				Performance: "registry.example.com/codewind-performance-amd64:0.13.0",
+				Gatekeeper:  "eclipse/codewind-gatekeeper-amd64@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			CurrentVersion:       "0.13.0",
+			UpgradeState:         UpgradeStateComplete,
+			LastActivityTime:     &at,
+			CredentialsRotatedAt: &at,
+			CertificateNotAfter:  &at,
+			Storage: &StorageStatus{
+				StorageClassName: "fast",
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				RequestedSize:    "10Gi",
+				Capacity:         "10Gi",
+				ResizeState:      StorageResizeStateComplete,
+				Message:          "Resized",
+			},
+		},
+	}
+}
+
+func TestCodewindConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(codewind *Codewind)
+	}{
+		{"fully populated", func(codewind *Codewind) {}},
+		{"empty storage and pull secrets", func(codewind *Codewind) {
+			codewind.Spec.StorageSize = ""
+			codewind.Spec.Storage = &StorageSpec{}
+			codewind.Spec.ImagePullSecrets = []corev1.LocalObjectReference{}
+		}},
+		{"empty storage with a size", func(codewind *Codewind) {
+			codewind.Spec.Storage = &StorageSpec{}
+		}},
+		{"empty images and pod templates", func(codewind *Codewind) {
+			codewind.Spec.Images = &CodewindImages{}
+			codewind.Spec.PodTemplates = &CodewindPodTemplates{}
+			codewind.Spec.ImagePullPolicy = ""
+			codewind.Spec.ImagePullSecrets = nil
+		}},
+		{"unset optional fields", func(codewind *Codewind) {
+			codewind.ObjectMeta.Annotations = nil
+			codewind.Spec = CodewindSpec{Username: "jane"}
+			codewind.Status = CodewindStatus{}
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := populatedCodewind()
+			test.edit(original)
+			want := original.DeepCopy()
+
+			hub := &v1beta1.Codewind{}
+			if err := original.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo() returned %v", err)
+			}
+			got := &Codewind{}
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() returned %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the Codewind\ngot:  %+v\nwant: %+v", got, want)
+			}
+			if !reflect.DeepEqual(original, want) {
+				t.Errorf("conversion changed the source Codewind\ngot:  %+v\nwant: %+v", original, want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -19,20 +20,208 @@ import (
 type CodewindSpec struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 
-	// KeycloakDeployment : name of the keycloak deployment used by this instance of codewind
+	// KeycloakDeployment : name of the keycloak deployment used by this instance of codewind, required unless
+	// externalAuth is set
 	// +kubebuilder:validation:Pattern=^[A-Za-z0-9/-]*$
-	KeycloakDeployment string `json:"keycloakDeployment"`
+	KeycloakDeployment string `json:"keycloakDeployment,omitempty"`
+
+	// KeycloakNamespace : namespace of the keycloak deployment, defaults to the namespace of this instance
+	// +kubebuilder:validation:Pattern=^[a-z0-9-]*$
+	KeycloakNamespace string `json:"keycloakNamespace,omitempty"`
+
+	// ExternalAuth : registers this instance with an existing Keycloak or RH-SSO server instead of an operator
+	// managed Keycloak, takes precedence over keycloakDeployment
+	ExternalAuth *ExternalAuthSpec `json:"externalAuth,omitempty"`
 
 	// Developer username assigned to this instance
 	// +kubebuilder:validation:Pattern=^[A-Za-z0-9/-]*$
 	Username string `json:"username"`
 
-	// Codewind Storage size
+	// Codewind Storage size, defaults to storageCodewindSize of the operator config map
 	// +kubebuilder:validation:Pattern=[0-9]*Gi$
-	StorageSize string `json:"storageSize"`
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// Storage : storage class and access modes of the PFE PVC
+	Storage *StorageSpec `json:"storage,omitempty"`
+
+	// LogLevel within pods, defaults to logLevel of the operator config map
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Version : Codewind release to deploy, used as the tag of every Codewind image that does not set its own
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`
+	Version string `json:"version,omitempty"`
+
+	// Suspended : scales the Codewind deployments to zero while keeping the workspace, storage and Keycloak registration
+	Suspended bool `json:"suspended,omitempty"`
+
+	// IdleTimeout : suspends the instance after the gatekeeper has seen no traffic for this long, overrides the
+	// operator config map, 0 disables idle culling
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+
+	// RotateCredentialsAt : regenerates the Keycloak client secret of this instance and restarts the gatekeeper once
+	// this time is reached and is later than status.credentialsRotatedAt
+	RotateCredentialsAt *metav1.Time `json:"rotateCredentialsAt,omitempty"`
+
+	// Images : optional container image overrides for the Codewind components
+	Images *CodewindImages `json:"images,omitempty"`
+
+	// ImagePullPolicy : pull policy of the Codewind containers, defaults to the operator config map
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets : secrets used to pull the Codewind images, added to the operator config map defaults
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// PodTemplates : optional resource and scheduling settings for the Codewind pods
+	PodTemplates *CodewindPodTemplates `json:"podTemplates,omitempty"`
+
+	// IssuerRef : cert-manager issuer that signs the gatekeeper certificate, overrides the operator config map. The
+	// operator generates a self-signed certificate when no issuer is set
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+}
+
+// ExternalAuthSpec : connection details of a Keycloak or RH-SSO server that is not managed by the operator
+type ExternalAuthSpec struct {
+	// URL : base URL of the server without the /auth context path, for example https://sso.example.com
+	// +kubebuilder:validation:Pattern=`^https?://[^/]+$`
+	URL string `json:"url"`
+
+	// Realm : realm to register the instance in, defaults to the operator config map defaultRealm
+	Realm string `json:"realm,omitempty"`
+
+	// CredentialsSecret : secret in the namespace of this CR holding the keycloak-admin-user and
+	// keycloak-admin-password used to register the instance
+	CredentialsSecret string `json:"credentialsSecret"`
+
+	// CABundleConfigMap : optional config map in the namespace of this CR whose ca.crt key holds the PEM encoded
+	// certificates that sign the server certificate
+	CABundleConfigMap string `json:"caBundleConfigMap,omitempty"`
+}
+
+// IssuerReference : cert-manager Issuer or ClusterIssuer used to request a certificate
+type IssuerReference struct {
+	// Name : name of the issuer
+	Name string `json:"name"`
+
+	// Kind : Issuer, in the namespace of the certificate, or ClusterIssuer, defaults to ClusterIssuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+
+	// Group : API group of the issuer, defaults to cert-manager.io
+	Group string `json:"group,omitempty"`
+}
+
+// StorageSpec : storage class and access modes of a PVC created by the operator, both are fixed once the PVC exists
+type StorageSpec struct {
+	// StorageClassName : storage class of the PVC, defaults to the storage class of the tenant defaults or the
+	// operator. Increasing the storage size expands the PVC online when this storage class allows volume expansion
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// AccessModes : access modes of the PVC, defaults to ReadWriteMany for Codewind and ReadWriteOnce for Keycloak
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+}
+
+// StorageResizeState : progress of the expansion of a PVC
+type StorageResizeState string
+
+const (
+	// StorageResizeStateResizing : the storage provider is expanding the volume
+	StorageResizeStateResizing StorageResizeState = "Resizing"
+
+	// StorageResizeStateFileSystemResizePending : the volume has been expanded and the file system is resized
+	// when the pod mounting it restarts
+	StorageResizeStateFileSystemResizePending StorageResizeState = "FileSystemResizePending"
+
+	// StorageResizeStateComplete : the capacity of the PVC satisfies the requested size
+	StorageResizeStateComplete StorageResizeState = "Complete"
+
+	// StorageResizeStateUnsupported : the requested size cannot be applied, the storage class does not allow
+	// volume expansion or the size is smaller than the PVC
+	StorageResizeStateUnsupported StorageResizeState = "Unsupported"
+
+	// StorageResizeStateFailed : the expansion request was rejected
+	StorageResizeStateFailed StorageResizeState = "Failed"
+)
+
+// StorageStatus : observed state of a PVC created by the operator
+type StorageStatus struct {
+	// StorageClassName : storage class of the PVC
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// AccessModes : access modes of the PVC
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// RequestedSize : size requested by the spec
+	RequestedSize string `json:"requestedSize,omitempty"`
+
+	// Capacity : size of the volume bound to the PVC
+	Capacity string `json:"capacity,omitempty"`
+
+	// ResizeState : progress of the expansion of the PVC to the requested size
+	ResizeState StorageResizeState `json:"resizeState,omitempty"`
+
+	// Message : details of the resize state
+	Message string `json:"message,omitempty"`
+}
+
+// ImageSpec : reference to a container image, any field left empty is taken from the operator defaults
+type ImageSpec struct {
+	// Repository : image name including any registry, for example quay.io/eclipse/codewind-pfe-amd64
+	Repository string `json:"repository,omitempty"`
+
+	// Tag : image tag, ignored when a digest is set
+	Tag string `json:"tag,omitempty"`
+
+	// Digest : image digest, for example sha256:0a1b...
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
+}
+
+// CodewindImages : container image overrides for each Codewind component
+type CodewindImages struct {
+	// PFE : image of the Codewind PFE container
+	PFE *ImageSpec `json:"pfe,omitempty"`
+
+	// Performance : image of the Codewind performance container
+	Performance *ImageSpec `json:"performance,omitempty"`
+
+	// Gatekeeper : image of the Codewind gatekeeper container
+	Gatekeeper *ImageSpec `json:"gatekeeper,omitempty"`
+}
+
+// PodTemplateOverrides : resource and scheduling settings merged into a pod built by the operator, any field left
+// empty is taken from the operator config map defaults
+type PodTemplateOverrides struct {
+	// Resources : compute resource requests and limits of the main container
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector : labels a node must have for the pod to be scheduled on it
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations : taints the pod tolerates
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity : node and pod affinity rules of the pod
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+}
 
-	// LogLevel within pods
-	LogLevel string `json:"logLevel"`
+// CodewindPodTemplates : resource and scheduling settings for each Codewind component
+type CodewindPodTemplates struct {
+	// PFE : settings of the Codewind PFE pod
+	PFE *PodTemplateOverrides `json:"pfe,omitempty"`
+
+	// Performance : settings of the Codewind performance pod
+	Performance *PodTemplateOverrides `json:"performance,omitempty"`
+
+	// Gatekeeper : settings of the Codewind gatekeeper pod
+	Gatekeeper *PodTemplateOverrides `json:"gatekeeper,omitempty"`
+}
+
+// CodewindStatusImages : container images resolved by the operator for each Codewind component
+type CodewindStatusImages struct {
+	PFE         string `json:"pfe,omitempty"`
+	Performance string `json:"performance,omitempty"`
+	Gatekeeper  string `json:"gatekeeper,omitempty"`
 }
 
 // CodewindStatus defines the observed state of Codewind
@@ -46,6 +235,114 @@ type CodewindStatus struct {
 
 	// Keycloak Configuration status
 	KeycloakStatus string `json:"keycloakStatus"`
+
+	// Phase : summary of the conditions of this Codewind instance
+	Phase CodewindPhase `json:"phase,omitempty"`
+
+	// ObservedGeneration : the most recent generation of the spec acted on by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions : latest observations of the state of this Codewind instance
+	Conditions []CodewindCondition `json:"conditions,omitempty"`
+
+	// Images : container images in use by this Codewind instance
+	Images CodewindStatusImages `json:"images,omitempty"`
+
+	// CurrentVersion : Codewind release that every component has finished rolling out
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// UpgradeState : progress of the rollout to the requested version
+	UpgradeState UpgradeState `json:"upgradeState,omitempty"`
+
+	// LastActivityTime : last time the gatekeeper was seen serving requests while idle culling is enabled
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// CredentialsRotatedAt : time the Keycloak client secret of this instance was last regenerated
+	CredentialsRotatedAt *metav1.Time `json:"credentialsRotatedAt,omitempty"`
+
+	// CertificateNotAfter : expiry time of the gatekeeper TLS certificate
+	CertificateNotAfter *metav1.Time `json:"certificateNotAfter,omitempty"`
+
+	// Storage : observed state of the PFE PVC
+	Storage *StorageStatus `json:"storage,omitempty"`
+}
+
+// UpgradeState : progress of a version rollout
+type UpgradeState string
+
+const (
+	// UpgradeStateProgressing : the deployments are rolling out the requested version
+	UpgradeStateProgressing UpgradeState = "Progressing"
+
+	// UpgradeStateComplete : every deployment is available at the requested version
+	UpgradeStateComplete UpgradeState = "Complete"
+
+	// UpgradeStateFailed : a deployment exceeded its progress deadline while rolling out the requested version
+	UpgradeStateFailed UpgradeState = "Failed"
+)
+
+// CodewindPhase : summarised state of a Codewind instance
+type CodewindPhase string
+
+const (
+	// CodewindPhasePending : resources are being created or the Keycloak deployment is not yet available
+	CodewindPhasePending CodewindPhase = "Pending"
+
+	// CodewindPhaseStarting : registered with Keycloak, waiting for the Codewind pods to become ready
+	CodewindPhaseStarting CodewindPhase = "Starting"
+
+	// CodewindPhaseRunning : all conditions are satisfied
+	CodewindPhaseRunning CodewindPhase = "Running"
+
+	// CodewindPhaseFailed : a condition reported an error that needs attention
+	CodewindPhaseFailed CodewindPhase = "Failed"
+
+	// CodewindPhaseSuspended : the Codewind deployments have been scaled to zero
+	CodewindPhaseSuspended CodewindPhase = "Suspended"
+)
+
+// CodewindConditionType : type of a Codewind status condition
+type CodewindConditionType string
+
+const (
+	// CodewindConditionKeycloakRegistered : the instance has been registered as a client of its Keycloak realm
+	CodewindConditionKeycloakRegistered CodewindConditionType = "KeycloakRegistered"
+
+	// CodewindConditionStorageBound : the PFE workspace PVC is bound
+	CodewindConditionStorageBound CodewindConditionType = "StorageBound"
+
+	// CodewindConditionPFEReady : the PFE deployment has available replicas
+	CodewindConditionPFEReady CodewindConditionType = "PFEReady"
+
+	// CodewindConditionGatekeeperReady : the Gatekeeper deployment has available replicas
+	CodewindConditionGatekeeperReady CodewindConditionType = "GatekeeperReady"
+
+	// CodewindConditionActivityTracked : the gatekeeper activity counter used to suspend idle instances can be read
+	CodewindConditionActivityTracked CodewindConditionType = "ActivityTracked"
+
+	// CodewindConditionReady : all other conditions are satisfied
+	CodewindConditionReady CodewindConditionType = "Ready"
+)
+
+// CodewindCondition : an observation of one aspect of a Codewind instance
+type CodewindCondition struct {
+	// Type of the condition
+	Type CodewindConditionType `json:"type"`
+
+	// Status of the condition, one of True, False or Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// ObservedGeneration : generation of the spec this condition was set against
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastTransitionTime : last time the condition changed status
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason : machine readable reason for the last transition
+	Reason string `json:"reason,omitempty"`
+
+	// Message : human readable details of the last transition
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -59,6 +356,8 @@ type CodewindStatus struct {
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",priority=0,description="Age of the resource"
 // +kubebuilder:printcolumn:name="Keycloak",type="string",JSONPath=".spec.keycloakDeployment",priority=0,description="Deployment reference name"
 // +kubebuilder:printcolumn:name="Registration",type="string",JSONPath=".status.keycloakStatus",priority=0,description="Keycloak configuration status"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",priority=0,description="Summarised state"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.currentVersion",priority=1,description="Deployed Codewind version"
 // +kubebuilder:printcolumn:name="AccessURL",type="string",JSONPath=".status.accessURL",priority=0,description="Exposed route"
 type Codewind struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -12,15 +12,99 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // KeycloakSpec defines the desired state of Keycloak
 type KeycloakSpec struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// StorageSize : Size of the Keycloak PVC
+	// StorageSize : Size of the Keycloak PVC, defaults to storageKeycloakSize of the operator config map
 	// +kubebuilder:validation:Pattern=[0-9]*Gi$
-	StorageSize string `json:"storageSize"`
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// Storage : storage class and access modes of the Keycloak PVC
+	Storage *StorageSpec `json:"storage,omitempty"`
+
+	// Version : Keycloak release to deploy, used as the image tag when the image does not set its own
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`
+	Version string `json:"version,omitempty"`
+
+	// Images : optional container image overrides for Keycloak
+	Images *KeycloakImages `json:"images,omitempty"`
+
+	// ImagePullPolicy : pull policy of the Keycloak container, defaults to the operator config map
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets : secrets used to pull the Keycloak image, added to the operator config map defaults
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// PodTemplates : optional resource and scheduling settings for the Keycloak pod
+	PodTemplates *KeycloakPodTemplates `json:"podTemplates,omitempty"`
+
+	// Replicas : number of Keycloak pods. More than one replica runs Keycloak clustered and requires an external
+	// database
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Database : optional external database for the Keycloak data, defaults to an embedded H2 database on the
+	// Keycloak PVC
+	Database *KeycloakDatabase `json:"database,omitempty"`
+
+	// RotateCredentialsAt : regenerates the Keycloak admin password, then the client secret of every Codewind
+	// instance registered with this Keycloak, once this time is reached and is later than
+	// status.credentialsRotatedAt
+	RotateCredentialsAt *metav1.Time `json:"rotateCredentialsAt,omitempty"`
+
+	// IssuerRef : cert-manager issuer that signs the Keycloak certificate, overrides the operator config map. The
+	// operator generates a self-signed certificate when no issuer is set
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+}
+
+// KeycloakDatabaseVendor : type of database used by Keycloak
+type KeycloakDatabaseVendor string
+
+// Supported Keycloak databases
+const (
+	KeycloakDatabaseH2       KeycloakDatabaseVendor = "h2"
+	KeycloakDatabasePostgres KeycloakDatabaseVendor = "postgres"
+	KeycloakDatabaseMySQL    KeycloakDatabaseVendor = "mysql"
+	KeycloakDatabaseMariaDB  KeycloakDatabaseVendor = "mariadb"
+)
+
+// KeycloakDatabase : connection details of the database used by Keycloak
+type KeycloakDatabase struct {
+	// Vendor : type of the database, h2 keeps the embedded database on the Keycloak PVC
+	// +kubebuilder:validation:Enum=h2;postgres;mysql;mariadb
+	Vendor KeycloakDatabaseVendor `json:"vendor"`
+
+	// Host : host name or address of the database server, required unless the vendor is h2
+	Host string `json:"host,omitempty"`
+
+	// Port : port of the database server, defaults to 5432 for postgres and 3306 for mysql and mariadb
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// Database : name of the database, defaults to keycloak
+	Database string `json:"database,omitempty"`
+
+	// CredentialsSecret : secret in the namespace of this CR holding the username and password of the database
+	// user, required unless the vendor is h2
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// KeycloakImages : container image overrides for Keycloak
+type KeycloakImages struct {
+	// Keycloak : image of the Keycloak container
+	Keycloak *ImageSpec `json:"keycloak,omitempty"`
+}
+
+// KeycloakPodTemplates : resource and scheduling settings for Keycloak
+type KeycloakPodTemplates struct {
+	// Keycloak : settings of the Keycloak pod
+	Keycloak *PodTemplateOverrides `json:"keycloak,omitempty"`
 }
 
 // KeycloakStatus defines the observed state of Keycloak
@@ -29,6 +113,28 @@ type KeycloakStatus struct {
 	Phase        string `json:"phase"`
 	AccessURL    string `json:"url"`
 	DefaultRealm string `json:"defaultRealm"`
+
+	// Image : container image in use by Keycloak
+	Image string `json:"image,omitempty"`
+
+	// CurrentVersion : Keycloak release that has finished rolling out
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// UpgradeState : progress of the rollout to the requested version
+	UpgradeState UpgradeState `json:"upgradeState,omitempty"`
+
+	// CredentialsRotatedAt : time the Keycloak admin password was last regenerated
+	CredentialsRotatedAt *metav1.Time `json:"credentialsRotatedAt,omitempty"`
+
+	// CertificateNotAfter : expiry time of the Keycloak TLS certificate
+	CertificateNotAfter *metav1.Time `json:"certificateNotAfter,omitempty"`
+
+	// Storage : observed state of the Keycloak PVC, not set when Keycloak uses an external database
+	Storage *StorageStatus `json:"storage,omitempty"`
+
+	// Message : explains why the deployment differs from the spec, such as running a single replica without an
+	// external database, or why the spec cannot be applied
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,94 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakBackupSpec defines the desired state of KeycloakBackup
+type KeycloakBackupSpec struct {
+	// KeycloakDeployment : name of the Keycloak, in the namespace of this CR, whose realm is backed up
+	// +kubebuilder:validation:Pattern=^[A-Za-z0-9/-]*$
+	KeycloakDeployment string `json:"keycloakDeployment"`
+
+	// Realm : realm to back up, defaults to the operator config map defaultRealm
+	Realm string `json:"realm,omitempty"`
+
+	// Schedule : cron schedule of the backups in UTC, for example "0 2 * * *". A single backup is taken when empty
+	Schedule string `json:"schedule,omitempty"`
+
+	// Retain : number of backups to keep, older backups are deleted. Defaults to 7
+	// +kubebuilder:validation:Minimum=1
+	Retain int32 `json:"retain,omitempty"`
+}
+
+// BackupPhase : outcome of the latest backup or restore
+type BackupPhase string
+
+// Backup and restore phases
+const (
+	BackupPhasePending   BackupPhase = "Pending"
+	BackupPhaseCompleted BackupPhase = "Completed"
+	BackupPhaseFailed    BackupPhase = "Failed"
+)
+
+// KeycloakBackupStatus defines the observed state of KeycloakBackup
+type KeycloakBackupStatus struct {
+	// Phase : outcome of the latest backup
+	Phase BackupPhase `json:"phase,omitempty"`
+
+	// Message : details of the latest backup, including the reason of a failure
+	Message string `json:"message,omitempty"`
+
+	// LastBackupTime : time of the latest successful backup
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// LastBackupSecret : secret holding the latest successful backup
+	LastBackupSecret string `json:"lastBackupSecret,omitempty"`
+
+	// LastScheduleTime : time the latest successful scheduled backup ran, the next backup follows it on the schedule
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextBackupTime : time of the next scheduled backup
+	NextBackupTime *metav1.Time `json:"nextBackupTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KeycloakBackup is the Schema for the keycloakbackups API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=keycloakbackups,scope=Namespaced
+// +kubebuilder:printcolumn:name="Keycloak",type="string",JSONPath=".spec.keycloakDeployment",priority=0,description="Keycloak reference name"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",priority=0,description="Backup schedule"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",priority=0,description="Outcome of the latest backup"
+// +kubebuilder:printcolumn:name="LastBackup",type="date",JSONPath=".status.lastBackupTime",priority=0,description="Time of the latest backup"
+// +kubebuilder:printcolumn:name="Secret",type="string",JSONPath=".status.lastBackupSecret",priority=1,description="Secret holding the latest backup"
+type KeycloakBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              KeycloakBackupSpec   `json:"spec,omitempty"`
+	Status            KeycloakBackupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KeycloakBackupList contains a list of KeycloakBackup
+type KeycloakBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakBackup{}, &KeycloakBackupList{})
+}
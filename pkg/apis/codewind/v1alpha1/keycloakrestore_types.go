@@ -0,0 +1,76 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeycloakRestoreSpec defines the desired state of KeycloakRestore
+type KeycloakRestoreSpec struct {
+	// KeycloakDeployment : name of the Keycloak, in the namespace of this CR, the backup is imported into
+	// +kubebuilder:validation:Pattern=^[A-Za-z0-9/-]*$
+	KeycloakDeployment string `json:"keycloakDeployment"`
+
+	// BackupSecret : secret, in the namespace of this CR, holding the backup created by a KeycloakBackup
+	BackupSecret string `json:"backupSecret"`
+
+	// Realm : realm to import into, defaults to the realm the backup was taken from
+	Realm string `json:"realm,omitempty"`
+}
+
+// KeycloakRestoreStatus defines the observed state of KeycloakRestore
+type KeycloakRestoreStatus struct {
+	// Phase : progress of the restore
+	Phase BackupPhase `json:"phase,omitempty"`
+
+	// Message : details of the restore, including the reason of a failure
+	Message string `json:"message,omitempty"`
+
+	// Attempts : number of failed attempts to import the backup
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// RestoreTime : time the backup was imported
+	RestoreTime *metav1.Time `json:"restoreTime,omitempty"`
+
+	// ObservedGeneration : generation of the spec the phase and attempts apply to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KeycloakRestore is the Schema for the keycloakrestores API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=keycloakrestores,scope=Namespaced
+// +kubebuilder:printcolumn:name="Keycloak",type="string",JSONPath=".spec.keycloakDeployment",priority=0,description="Keycloak reference name"
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupSecret",priority=0,description="Secret holding the backup"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",priority=0,description="Progress of the restore"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",priority=0,description="Age of the resource"
+type KeycloakRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              KeycloakRestoreSpec   `json:"spec,omitempty"`
+	Status            KeycloakRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KeycloakRestoreList contains a list of KeycloakRestore
+type KeycloakRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeycloakRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeycloakRestore{}, &KeycloakRestoreList{})
+}
@@ -0,0 +1,14 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+// Package v1beta1 contains API Schema definitions for the codewind v1beta1 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=codewind.eclipse.org
+package v1beta1
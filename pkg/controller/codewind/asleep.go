@@ -0,0 +1,121 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package codewind
+
+import (
+	"context"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	"github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileAsleepBackend : runs the asleep page while the instance is suspended so that the gatekeeper host explains
+// why Codewind is unavailable, and removes it once the instance resumes
+func (r *ReconcileCodewind) reconcileAsleepBackend(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind) error {
+	name := types.NamespacedName{Name: deploymentOptions.CodewindAsleepName, Namespace: codewind.Namespace}
+	if !isSuspended(codewind) {
+		for _, obj := range []runtime.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.ConfigMap{}} {
+			err := r.client.Get(context.TODO(), name, obj)
+			if err != nil && k8serr.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				reqLogger.Error(err, "Failed to get the asleep page", "Namespace", name.Namespace, "Name", name.Name)
+				return err
+			}
+			reqLogger.Info("Removing the asleep page", "Namespace", name.Namespace, "Name", name.Name)
+			err = r.client.Delete(context.TODO(), obj)
+			if err != nil && !k8serr.IsNotFound(err) {
+				reqLogger.Error(err, "Failed to remove the asleep page", "Namespace", name.Namespace, "Name", name.Name)
+				return err
+			}
+		}
+		return nil
+	}
+
+	newConfigMap := r.configMapForCodewindAsleep(codewind, deploymentOptions)
+	configMap := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), name, configMap)
+	if err != nil && k8serr.IsNotFound(err) {
+		reqLogger.Info("Creating the asleep page config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
+		err = r.client.Create(context.TODO(), newConfigMap)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newConfigMap, err)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			reqLogger.Error(err, "Failed to create the asleep page config map.", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
+			return err
+		}
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get the asleep page config map.")
+		return err
+	} else if !equality.Semantic.DeepEqual(configMap.Data, newConfigMap.Data) {
+		reqLogger.Info("Updating the asleep page config map to match the required data.", "Namespace", configMap.Namespace, "Name", configMap.Name)
+		configMap.Data = newConfigMap.Data
+		err = r.client.Update(context.TODO(), configMap)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update the asleep page config map.", "Namespace", configMap.Namespace, "Name", configMap.Name)
+			return err
+		}
+	}
+
+	newDeployment := r.deploymentForCodewindAsleep(codewind, deploymentOptions)
+	deployment := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(), name, deployment)
+	if err != nil && k8serr.IsNotFound(err) {
+		reqLogger.Info("Creating the asleep page deployment", "Namespace", newDeployment.Namespace, "Name", newDeployment.Name)
+		err = r.client.Create(context.TODO(), newDeployment)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newDeployment, err)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			reqLogger.Error(err, "Failed to create the asleep page deployment.", "Namespace", newDeployment.Namespace, "Name", newDeployment.Name)
+			return err
+		}
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get the asleep page deployment.")
+		return err
+	} else if util.MergeDeployment(deployment, newDeployment) {
+		reqLogger.Info("Updating the asleep page deployment to match the required spec.", "Namespace", deployment.Namespace, "Name", deployment.Name)
+		err = r.client.Update(context.TODO(), deployment)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update the asleep page deployment.", "Namespace", deployment.Namespace, "Name", deployment.Name)
+			return err
+		}
+	}
+
+	newService := r.serviceForCodewindAsleep(codewind, deploymentOptions)
+	service := &corev1.Service{}
+	err = r.client.Get(context.TODO(), name, service)
+	if err != nil && k8serr.IsNotFound(err) {
+		reqLogger.Info("Creating the asleep page service", "Namespace", newService.Namespace, "Name", newService.Name)
+		err = r.client.Create(context.TODO(), newService)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newService, err)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			reqLogger.Error(err, "Failed to create the asleep page service.", "Namespace", newService.Namespace, "Name", newService.Name)
+			return err
+		}
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get the asleep page service.")
+		return err
+	} else if util.MergeService(service, newService) {
+		reqLogger.Info("Updating the asleep page service to match the required spec.", "Namespace", service.Namespace, "Name", service.Name)
+		err = r.client.Update(context.TODO(), service)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update the asleep page service.", "Namespace", service.Namespace, "Name", service.Name)
+			return err
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ package codewind
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
 	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
@@ -24,6 +25,7 @@ import (
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -65,9 +67,7 @@ func (r *ReconcileCodewind) pvcForCodewind(codewind *codewindv1alpha1.Codewind,
 			Labels:    labels,
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: []corev1.PersistentVolumeAccessMode{
-				"ReadWriteMany",
-			},
+			AccessModes: util.PVCAccessModes(codewind.Spec.Storage, corev1.ReadWriteMany),
 			Resources: corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{
 					corev1.ResourceStorage: resource.MustParse(storageSize),
@@ -76,7 +76,8 @@ func (r *ReconcileCodewind) pvcForCodewind(codewind *codewindv1alpha1.Codewind,
 		},
 	}
 
-	// If a storage class was passed in, set it in the PVC
+	// The storage class of the CR takes precedence over the one passed in
+	storageClassName = util.PVCStorageClassName(codewind.Spec.Storage, storageClassName)
 	if storageClassName != "" {
 		pvc.Spec.StorageClassName = &storageClassName
 	}
@@ -88,7 +89,7 @@ func (r *ReconcileCodewind) pvcForCodewind(codewind *codewindv1alpha1.Codewind,
 
 func (r *ReconcileCodewind) deploymentForCodewindPerformance(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, ingressDomain string) *appsv1.Deployment {
 	ls := labelsForCodewindPerformance(deploymentOptions)
-	replicas := int32(1)
+	replicas := deploymentOptions.CodewindReplicas
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      defaults.PrefixCodewindPerformance + "-" + deploymentOptions.WorkspaceID,
@@ -106,10 +107,11 @@ func (r *ReconcileCodewind) deploymentForCodewindPerformance(codewind *codewindv
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: deploymentOptions.CodewindServiceAccountName,
+					ImagePullSecrets:   deploymentOptions.ImagePullSecrets,
 					Containers: []corev1.Container{{
 						Name:            defaults.PrefixCodewindPerformance,
-						Image:           defaults.CodewindPerformanceImage + ":" + defaults.CodewindPerformanceImageTag,
-						ImagePullPolicy: corev1.PullAlways,
+						Image:           deploymentOptions.CodewindPerformanceImage,
+						ImagePullPolicy: deploymentOptions.ImagePullPolicy,
 						Env: []corev1.EnvVar{
 							{
 								Name:  "IN_K8",
@@ -132,6 +134,7 @@ func (r *ReconcileCodewind) deploymentForCodewindPerformance(codewind *codewindv
 			},
 		},
 	}
+	util.ApplyPodTemplate(&dep.Spec.Template.Spec, deploymentOptions.CodewindPerformancePodTemplate)
 	// Set Codewind instance as the owner of this deployment
 	controllerutil.SetControllerReference(codewind, dep, r.scheme)
 	return dep
@@ -140,12 +143,10 @@ func (r *ReconcileCodewind) deploymentForCodewindPerformance(codewind *codewindv
 // deploymentForCodewindPFE returns a Codewind dployment object
 func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, isOnOpenshift bool, keycloakRealm string, authHost string, logLevel string, ingressDomain string) *appsv1.Deployment {
 	ls := labelsForCodewindPFE(deploymentOptions)
-	replicas := int32(1)
+	replicas := deploymentOptions.CodewindReplicas
 	runAsPrivileged := true
-	loglevel := "info"
-	if codewind.Spec.LogLevel != "" {
-		loglevel = codewind.Spec.LogLevel
-	}
+	caBundleOptional := true
+	loglevel := util.ValueOrDefault(logLevel, defaults.CodewindLogLevel)
 	volumes := []corev1.Volume{
 		{
 			Name: "shared-workspace",
@@ -158,6 +159,15 @@ func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.
 		{
 			Name: "buildah-volume",
 		},
+		{
+			Name: "ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: defaults.CABundleConfigMapName},
+					Optional:             &caBundleOptional,
+				},
+			},
+		},
 	}
 	volumeMounts := []corev1.VolumeMount{
 		{
@@ -169,6 +179,11 @@ func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.
 			Name:      "buildah-volume",
 			MountPath: "/var/lib/containers",
 		},
+		{
+			Name:      "ca-bundle",
+			MountPath: defaults.CABundleMountPath,
+			ReadOnly:  true,
+		},
 	}
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -178,6 +193,8 @@ func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
+			// Without ReadWriteMany the new pod cannot attach the workspace PVC while the old one runs
+			Strategy: util.PVCDeploymentStrategy(util.PVCAccessModes(codewind.Spec.Storage, corev1.ReadWriteMany)),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: ls,
 			},
@@ -187,11 +204,12 @@ func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: deploymentOptions.CodewindServiceAccountName,
+					ImagePullSecrets:   deploymentOptions.ImagePullSecrets,
 					Volumes:            volumes,
 					Containers: []corev1.Container{{
 						Name:            defaults.PrefixCodewindPFE,
-						Image:           defaults.CodewindImage + ":" + defaults.CodewindImageTag,
-						ImagePullPolicy: corev1.PullAlways,
+						Image:           deploymentOptions.CodewindPFEImage,
+						ImagePullPolicy: deploymentOptions.ImagePullPolicy,
 						SecurityContext: &corev1.SecurityContext{
 							Privileged: &runAsPrivileged,
 						},
@@ -243,7 +261,7 @@ func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.
 							},
 							{
 								Name:  "CODEWIND_VERSION",
-								Value: defaults.CodewindImageTag,
+								Value: deploymentOptions.CodewindVersion,
 							},
 							{
 								Name:  "OWNER_REF_NAME",
@@ -281,6 +299,10 @@ func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.
 								Name:  "LOG_LEVEL",
 								Value: loglevel,
 							},
+							{
+								Name:  "NODE_EXTRA_CA_CERTS", // trust the certificates signed by the operator CA
+								Value: defaults.CABundleMountPath + "/ca.crt",
+							},
 						},
 						Ports: []corev1.ContainerPort{
 							{ContainerPort: int32(defaults.PFEContainerPort)},
@@ -290,6 +312,7 @@ func (r *ReconcileCodewind) deploymentForCodewindPFE(codewind *codewindv1alpha1.
 			},
 		},
 	}
+	util.ApplyPodTemplate(&dep.Spec.Template.Spec, deploymentOptions.CodewindPFEPodTemplate)
 	// Set Codewind instance as the owner of the Deployment.
 	controllerutil.SetControllerReference(codewind, dep, r.scheme)
 	return dep
@@ -370,7 +393,7 @@ func (r *ReconcileCodewind) serviceForCodewindGatekeeper(codewind *codewindv1alp
 // deploymentForCodewindGatekeeper returns a Codewind deployment object
 func (r *ReconcileCodewind) deploymentForCodewindGatekeeper(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, isOnOpenshift bool, keycloakRealm string, keycloakClientID string, keycloakAuthURL string, ingressDomain string) *appsv1.Deployment {
 	ls := labelsForCodewindGatekeeper(deploymentOptions)
-	replicas := int32(1)
+	replicas := deploymentOptions.CodewindReplicas
 
 	// Replace any dash characters in the WorkspaceID to understore characters to match variable formats created by Kubernetes
 	workspaceServiceSuffix := strings.ReplaceAll(strings.ToUpper(deploymentOptions.WorkspaceID), "-", "_")
@@ -392,6 +415,7 @@ func (r *ReconcileCodewind) deploymentForCodewindGatekeeper(codewind *codewindv1
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: deploymentOptions.CodewindServiceAccountName,
+					ImagePullSecrets:   deploymentOptions.ImagePullSecrets,
 					Volumes: []corev1.Volume{{
 						Name: "tls-certs",
 						VolumeSource: corev1.VolumeSource{
@@ -402,8 +426,8 @@ func (r *ReconcileCodewind) deploymentForCodewindGatekeeper(codewind *codewindv1
 					}},
 					Containers: []corev1.Container{{
 						Name:            defaults.PrefixCodewindGatekeeper,
-						Image:           defaults.CodewindGatekeeperImage + ":" + defaults.CodewindGatekeeperImageTag,
-						ImagePullPolicy: corev1.PullAlways,
+						Image:           deploymentOptions.CodewindGatekeeperImage,
+						ImagePullPolicy: deploymentOptions.ImagePullPolicy,
 						VolumeMounts: []corev1.VolumeMount{{
 							MountPath: "/tlscerts",
 							Name:      "tls-certs",
@@ -465,6 +489,18 @@ func (r *ReconcileCodewind) deploymentForCodewindGatekeeper(codewind *codewindv1
 			},
 		},
 	}
+	// Restart the gatekeeper when its client secret is rotated or its certificate is renewed
+	annotations := map[string]string{}
+	if deploymentOptions.CodewindCredentialsRotatedAt != "" {
+		annotations[defaults.CodewindCredentialsRotatedAnnotation] = deploymentOptions.CodewindCredentialsRotatedAt
+	}
+	if deploymentOptions.CodewindCertificateNotAfter != "" {
+		annotations[defaults.CodewindCertificateNotAfterAnnotation] = deploymentOptions.CodewindCertificateNotAfter
+	}
+	if len(annotations) > 0 {
+		dep.Spec.Template.Annotations = annotations
+	}
+	util.ApplyPodTemplate(&dep.Spec.Template.Spec, deploymentOptions.CodewindGatekeeperPodTemplate)
 	// Set Codewind instance as the owner of the Deployment.
 	controllerutil.SetControllerReference(codewind, dep, r.scheme)
 	return dep
@@ -483,6 +519,9 @@ func (r *ReconcileCodewind) routeForCodewindGatekeeper(codewind *codewindv1alpha
 			Name:      deploymentOptions.CodewindGatekeeperIngressName,
 			Namespace: codewind.Namespace,
 			Labels:    ls,
+			Annotations: map[string]string{
+				defaults.CodewindStateAnnotation: deploymentOptions.CodewindState,
+			},
 		},
 		Spec: routev1.RouteSpec{
 			Host: deploymentOptions.CodewindGatekeeperIngressHost,
@@ -500,6 +539,17 @@ func (r *ReconcileCodewind) routeForCodewindGatekeeper(codewind *codewindv1alpha
 			},
 		},
 	}
+	// While suspended the router terminates TLS with the gatekeeper certificate and forwards to the asleep page
+	if deploymentOptions.CodewindState == defaults.CodewindStateAsleep {
+		route.Spec.Port.TargetPort = intstr.FromInt(defaults.AsleepContainerPort)
+		route.Spec.TLS = &routev1.TLSConfig{
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+			Termination:                   routev1.TLSTerminationEdge,
+			Certificate:                   deploymentOptions.CodewindGatekeeperTLSCert,
+			Key:                           deploymentOptions.CodewindGatekeeperTLSKey,
+		}
+		route.Spec.To.Name = deploymentOptions.CodewindAsleepName
+	}
 	// Set Codewind instance as the owner of the route.
 	controllerutil.SetControllerReference(codewind, route, r.scheme)
 	return route
@@ -513,8 +563,19 @@ func (r *ReconcileCodewind) ingressForCodewindGatekeeper(codewind *codewindv1alp
 		"ingress.bluemix.net/redirect-to-https":          "True",
 		"ingress.bluemix.net/ssl-services":               "ssl-service=" + deploymentOptions.CodewindGatekeeperServiceName,
 		"nginx.ingress.kubernetes.io/backend-protocol":   "HTTPS",
-		"kubernetes.io/ingress.class":                    "nginx",
 		"nginx.ingress.kubernetes.io/force-ssl-redirect": "true",
+		defaults.CodewindStateAnnotation:                 deploymentOptions.CodewindState,
+	}
+	if deploymentOptions.IngressClass != "" {
+		annotations["kubernetes.io/ingress.class"] = deploymentOptions.IngressClass
+	}
+	serviceName := deploymentOptions.CodewindGatekeeperServiceName
+	servicePort := defaults.GatekeeperContainerPort
+	// While suspended the ingress forwards to the asleep page, which is served over HTTP
+	if deploymentOptions.CodewindState == defaults.CodewindStateAsleep {
+		annotations["nginx.ingress.kubernetes.io/backend-protocol"] = "HTTP"
+		serviceName = deploymentOptions.CodewindAsleepName
+		servicePort = defaults.AsleepContainerPort
 	}
 	ingress := &extv1beta1.Ingress{
 		TypeMeta: metav1.TypeMeta{
@@ -543,8 +604,8 @@ func (r *ReconcileCodewind) ingressForCodewindGatekeeper(codewind *codewindv1alp
 								{
 									Path: "/",
 									Backend: extv1beta1.IngressBackend{
-										ServiceName: deploymentOptions.CodewindGatekeeperServiceName,
-										ServicePort: intstr.FromInt(defaults.GatekeeperContainerPort),
+										ServiceName: serviceName,
+										ServicePort: intstr.FromInt(servicePort),
 									},
 								},
 							},
@@ -559,6 +620,125 @@ func (r *ReconcileCodewind) ingressForCodewindGatekeeper(codewind *codewindv1alp
 	return ingress
 }
 
+// configMapForCodewindAsleep : builds the page served on the gatekeeper host while the instance is suspended, and the
+// httpd configuration that returns it for every path
+func (r *ReconcileCodewind) configMapForCodewindAsleep(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind) *corev1.ConfigMap {
+	page := "<!DOCTYPE html>\n" +
+		"<html>\n" +
+		"<head><title>Codewind is asleep</title></head>\n" +
+		"<body>\n" +
+		"<h1>Codewind is asleep</h1>\n" +
+		"<p>The Codewind instance " + codewind.Name + " in namespace " + codewind.Namespace + " is suspended. " +
+		"Its workspace is kept and it resumes with the same address once it is woken up.</p>\n" +
+		"</body>\n" +
+		"</html>\n"
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentOptions.CodewindAsleepName,
+			Namespace: codewind.Namespace,
+			Labels:    labelsForCodewindAsleep(deploymentOptions),
+		},
+		Data: map[string]string{
+			"index.html": page,
+			"httpd.conf": "E404:/www/index.html\n",
+		},
+	}
+	// Set Codewind instance as the owner of the config map.
+	controllerutil.SetControllerReference(codewind, configMap, r.scheme)
+	return configMap
+}
+
+// deploymentForCodewindAsleep : builds the deployment that serves the asleep page while the instance is suspended
+func (r *ReconcileCodewind) deploymentForCodewindAsleep(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind) *appsv1.Deployment {
+	ls := labelsForCodewindAsleep(deploymentOptions)
+	replicas := int32(1)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentOptions.CodewindAsleepName,
+			Namespace: codewind.Namespace,
+			Labels:    ls,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: ls,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: ls,
+				},
+				Spec: corev1.PodSpec{
+					ImagePullSecrets: deploymentOptions.ImagePullSecrets,
+					Volumes: []corev1.Volume{{
+						Name: "asleep-page",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: deploymentOptions.CodewindAsleepName},
+							},
+						},
+					}},
+					Containers: []corev1.Container{{
+						Name:            defaults.PrefixCodewindAsleep,
+						Image:           deploymentOptions.CodewindAsleepImage,
+						ImagePullPolicy: deploymentOptions.ImagePullPolicy,
+						Command:         []string{"httpd", "-f", "-p", strconv.Itoa(defaults.AsleepContainerPort), "-h", "/www", "-c", "/www/httpd.conf"},
+						VolumeMounts: []corev1.VolumeMount{{
+							MountPath: "/www",
+							Name:      "asleep-page",
+							ReadOnly:  true,
+						}},
+						Ports: []corev1.ContainerPort{
+							{ContainerPort: int32(defaults.AsleepContainerPort)},
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("5m"),
+								corev1.ResourceMemory: resource.MustParse("8Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("50m"),
+								corev1.ResourceMemory: resource.MustParse("32Mi"),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	// Set Codewind instance as the owner of the Deployment.
+	controllerutil.SetControllerReference(codewind, dep, r.scheme)
+	return dep
+}
+
+// serviceForCodewindAsleep : builds the service that the gatekeeper route or ingress targets while the instance is
+// suspended
+func (r *ReconcileCodewind) serviceForCodewindAsleep(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind) *corev1.Service {
+	ls := labelsForCodewindAsleep(deploymentOptions)
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentOptions.CodewindAsleepName,
+			Namespace: codewind.Namespace,
+			Labels:    ls,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: ls,
+			Ports: []corev1.ServicePort{
+				{
+					Port: int32(defaults.AsleepContainerPort),
+					Name: defaults.PrefixCodewindAsleep + "-http",
+				},
+			},
+		},
+	}
+	// Set Codewind instance as the owner of the Service.
+	controllerutil.SetControllerReference(codewind, service, r.scheme)
+	return service
+}
+
 // buildGatekeeperSessionSecret :  builds a session secret for gatekeeper
 func (r *ReconcileCodewind) buildGatekeeperSecretSession(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, sessionSecretValue string) *corev1.Secret {
 	metaLabels := labelsForCodewindGatekeeper(deploymentOptions)
@@ -582,9 +762,9 @@ func (r *ReconcileCodewind) buildGatekeeperSecretSession(codewind *codewindv1alp
 }
 
 // buildGatekeeperSecretTLS :  builds a TLS secret for gatekeeper
-func (r *ReconcileCodewind) buildGatekeeperSecretTLS(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, ingressDomain string) *corev1.Secret {
+func (r *ReconcileCodewind) buildGatekeeperSecretTLS(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, ingressDomain string, ca *util.CertificateAuthority) *corev1.Secret {
 	metaLabels := labelsForCodewindGatekeeper(deploymentOptions)
-	pemPrivateKey, pemPublicCert, _ := util.GenerateCertificate(deploymentOptions.CodewindGatekeeperIngressHost, deploymentOptions.CodewindGatekeeperTLSCertTitle)
+	pemPrivateKey, pemPublicCert, _ := util.GenerateCertificate(deploymentOptions.CodewindGatekeeperIngressHost, deploymentOptions.CodewindGatekeeperTLSCertTitle, ca)
 	secret := &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Secret",
@@ -600,11 +780,27 @@ func (r *ReconcileCodewind) buildGatekeeperSecretTLS(codewind *codewindv1alpha1.
 			"tls.key": pemPrivateKey,
 		},
 	}
+	if ca != nil {
+		secret.StringData["ca.crt"] = ca.CertificatePEM
+	}
 	// Set Codewind instance as the owner of this Secret.
 	controllerutil.SetControllerReference(codewind, secret, r.scheme)
 	return secret
 }
 
+// certificateForCodewindGatekeeper : builds a cert-manager Certificate that issues the gatekeeper TLS secret
+func (r *ReconcileCodewind) certificateForCodewindGatekeeper(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, issuer *codewindv1alpha1.IssuerReference, renewBefore time.Duration) *unstructured.Unstructured {
+	meta := metav1.ObjectMeta{
+		Name:      deploymentOptions.CodewindGatekeeperCertificateName,
+		Namespace: codewind.Namespace,
+		Labels:    labelsForCodewindGatekeeper(deploymentOptions),
+	}
+	certificate := util.BuildCertificate(meta, deploymentOptions.CodewindGatekeeperSecretTLSName, deploymentOptions.CodewindGatekeeperIngressHost, issuer, renewBefore)
+	// Set Codewind instance as the owner of this Certificate.
+	controllerutil.SetControllerReference(codewind, certificate, r.scheme)
+	return certificate
+}
+
 // buildGatekeeperSecretAuth :  builds an authentication detail secret for gatekeeper
 func (r *ReconcileCodewind) buildGatekeeperSecretAuth(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, keycloakClientKey string) *corev1.Secret {
 	metaLabels := labelsForCodewindGatekeeper(deploymentOptions)
@@ -640,3 +836,7 @@ func labelsForCodewindPerformance(deploymentOptions DeploymentOptionsCodewind) m
 func labelsForCodewindGatekeeper(deploymentOptions DeploymentOptionsCodewind) map[string]string {
 	return map[string]string{"app": defaults.PrefixCodewindGatekeeper, "codewindWorkspace": deploymentOptions.WorkspaceID, "codewindName": deploymentOptions.Name}
 }
+
+func labelsForCodewindAsleep(deploymentOptions DeploymentOptionsCodewind) map[string]string {
+	return map[string]string{"app": defaults.PrefixCodewindAsleep, "codewindWorkspace": deploymentOptions.WorkspaceID, "codewindName": deploymentOptions.Name}
+}
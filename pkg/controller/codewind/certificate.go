@@ -0,0 +1,108 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package codewind
+
+import (
+	"context"
+	"time"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// trackCertificateExpiry : reports the expiry of the gatekeeper certificate in the status and metrics, and passes it
+// to the gatekeeper pod template. Returns how long to wait before the certificate enters the renewal window
+func trackCertificateExpiry(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, deploymentOptions *DeploymentOptionsCodewind, secret *corev1.Secret, renewalWindow time.Duration) time.Duration {
+	notAfter, err := util.CertificateNotAfter(secret)
+	if err != nil {
+		reqLogger.Error(err, "Unable to read the Gatekeeper TLS certificate", "Namespace", secret.Namespace, "Name", secret.Name)
+		return 0
+	}
+	codewind.Status.CertificateNotAfter = &metav1.Time{Time: notAfter}
+	deploymentOptions.CodewindCertificateNotAfter = notAfter.UTC().Format(time.RFC3339)
+	metrics.SetCertificateExpiry(codewind.Namespace, "Codewind", codewind.Name, notAfter)
+	wait := time.Until(notAfter.Add(-renewalWindow))
+	if wait <= 0 {
+		// Renewal failed or is pending, check again soon
+		return time.Minute
+	}
+	return wait
+}
+
+// reconcileGatekeeperCertificate : requests the gatekeeper certificate from cert-manager when an issuer is set and
+// removes a Certificate requested earlier when it is not. Returns true when cert-manager issues the TLS secret
+func (r *ReconcileCodewind) reconcileGatekeeperCertificate(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, issuer *codewindv1alpha1.IssuerReference, renewalWindow time.Duration) (bool, error) {
+	certificate := util.NewCertificate()
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindGatekeeperCertificateName, Namespace: codewind.Namespace}, certificate)
+	if err != nil && !k8serr.IsNotFound(err) {
+		if issuer == nil && meta.IsNoMatchError(err) {
+			// cert-manager is not installed, there is nothing to remove
+			return false, nil
+		}
+		return false, err
+	}
+	found := err == nil
+
+	if issuer == nil {
+		if found && metav1.IsControlledBy(certificate, codewind) {
+			reqLogger.Info("Deleting the Gatekeeper Certificate, no issuer is set", "Namespace", certificate.GetNamespace(), "Name", certificate.GetName())
+			err = r.client.Delete(context.TODO(), certificate)
+			if err != nil && !k8serr.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	newCertificate := r.certificateForCodewindGatekeeper(codewind, deploymentOptions, issuer, renewalWindow)
+	if !found {
+		reqLogger.Info("Creating a new Gatekeeper Certificate", "Namespace", newCertificate.GetNamespace(), "Name", newCertificate.GetName(), "Issuer", issuer.Name)
+		err = r.client.Create(context.TODO(), newCertificate)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newCertificate, err)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			return false, err
+		}
+		return true, nil
+	}
+	if util.MergeCertificate(certificate, newCertificate) {
+		reqLogger.Info("Updating Gatekeeper Certificate to match the required spec.", "Namespace", certificate.GetNamespace(), "Name", certificate.GetName())
+		err = r.client.Update(context.TODO(), certificate)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// publishCABundle : copies the certificate of the operator CA into the CA bundle config map of the namespace of the
+// instance, which PFE mounts. The config map is shared by the instances of the namespace
+func (r *ReconcileCodewind) publishCABundle(codewind *codewindv1alpha1.Codewind, ca *util.CertificateAuthority) error {
+	if codewind.Namespace == util.GetOperatorNamespace() {
+		// Already published with the CA, and kept for as long as the CA
+		return nil
+	}
+	owner := &metav1.OwnerReference{
+		APIVersion: codewindv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Codewind",
+		Name:       codewind.Name,
+		UID:        codewind.UID,
+	}
+	return util.PublishCABundle(r.client, ca, defaults.CABundleConfigMapName, codewind.Namespace, owner)
+}
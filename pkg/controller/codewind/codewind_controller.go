@@ -13,15 +13,16 @@ package codewind
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
-	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
 	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+	"github.com/eclipse/codewind-operator/pkg/platform"
 	"github.com/eclipse/codewind-operator/pkg/security"
 	util "github.com/eclipse/codewind-operator/pkg/util"
 	"github.com/go-logr/logr"
@@ -31,10 +32,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	storagev1 "k8s.io/api/storage/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -64,20 +65,57 @@ type DeploymentOptionsCodewind struct {
 	CodewindPerformanceDeploymentName   string
 	CodewindPerformanceServiceName      string
 	CodewindGatekeeperServiceName       string
+	CodewindAsleepName                  string
+	CodewindAsleepImage                 string
+	CodewindGatekeeperTLSCert           string
+	CodewindGatekeeperTLSKey            string
 	CodewindGatekeeperSecretSessionName string
 	CodewindGatekeeperSecretTLSName     string
 	CodewindGatekeeperSecretAuthName    string
 	CodewindGatekeeperTLSCertTitle      string
+	CodewindGatekeeperCertificateName   string
+	CodewindCredentialsRotatedAt        string
+	CodewindCertificateNotAfter         string
 	CodewindGatekeeperDeploymentName    string
 	CodewindGatekeeperIngressName       string
 	CodewindGatekeeperIngressHost       string
+	CodewindVersion                     string
+	CodewindReplicas                    int32
+	CodewindState                       string
+	CodewindPFEImage                    string
+	CodewindPerformanceImage            string
+	CodewindGatekeeperImage             string
+	ImagePullPolicy                     corev1.PullPolicy
+	ImagePullSecrets                    []corev1.LocalObjectReference
+	CodewindPFEPodTemplate              *codewindv1alpha1.PodTemplateOverrides
+	CodewindPerformancePodTemplate      *codewindv1alpha1.PodTemplateOverrides
+	CodewindGatekeeperPodTemplate       *codewindv1alpha1.PodTemplateOverrides
+	IngressClass                        string
 }
 
 // OperatorConfigMapCodewind : Configuration fields saved in the config map
 type OperatorConfigMapCodewind struct {
-	IngressDomain string
-	StorageSize   string
-	DefaultRealm  string
+	IngressDomain          string
+	StorageSize            string
+	LogLevel               string
+	DefaultRealm           string
+	ImagePFE               string
+	ImagePerformance       string
+	ImageGatekeeper        string
+	ImageAsleep            string
+	ImageTag               string
+	ImagePullPolicy        string
+	ImagePullSecrets       string
+	PodTemplatePFE         string
+	PodTemplatePerformance string
+	PodTemplateGatekeeper  string
+	IdleTimeout            string
+	IdleActivityMetric     string
+	CertRenewalWindow      string
+	CertIssuerName         string
+	CertIssuerKind         string
+	HTTPClient             util.HTTPClientSettings
+	Platform               *platform.Profile
 }
 
 // Add creates a new Codewind Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -88,7 +126,7 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	reconciler := &ReconcileCodewind{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	reconciler := &ReconcileCodewind{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("codewind-controller"), activityCounts: make(map[types.UID]float64)}
 	operatorNamespace, _ := k8sutil.GetOperatorNamespace()
 	if operatorNamespace == "" {
 		operatorNamespace = "codewind"
@@ -99,11 +137,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
-	// Disable certificate validation checking
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-
 	// Create a new controller
-	c, err := controller.New("codewind-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("codewind-controller", mgr, controller.Options{Reconciler: metrics.InstrumentReconciler("codewind-controller", r)})
 	if err != nil {
 		return err
 	}
@@ -123,7 +158,37 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	return nil
+	// Watch the Deployments, Services, Ingresses and PVCs owned by Codewind so that any drift from the required spec is
+	// corrected and the progress of a PVC expansion is reported
+	ownedTypes := []runtime.Object{&appsv1.Deployment{}, &corev1.Service{}, &extv1beta1.Ingress{}, &corev1.PersistentVolumeClaim{}}
+	for _, ownedType := range ownedTypes {
+		err = c.Watch(&source.Kind{Type: ownedType}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &codewindv1alpha1.Codewind{},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Watch Keycloak so that a rotation of its credentials also rotates the client secrets registered with it
+	err = c.Watch(&source.Kind{Type: &codewindv1alpha1.Keycloak{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: codewindsForKeycloak(mgr.GetClient()),
+	}, keycloakCredentialsRotated)
+	if err != nil {
+		return err
+	}
+
+	// Watch the tenant defaults so that changes are applied to the Codewind instances of a namespace
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: util.RequestsForTenantDefaults(mgr.GetClient(), &codewindv1alpha1.CodewindList{}),
+	}, util.TenantDefaultsChanged)
+	if err != nil {
+		return err
+	}
+
+	// Report the number of instances in each phase on the operator metrics endpoint
+	return metrics.RegisterCodewindPhaseCollector(mgr.GetClient())
 }
 
 // blank assignment to verify that ReconcileCodewind implements reconcile.Reconciler
@@ -131,8 +196,13 @@ var _ reconcile.Reconciler = &ReconcileCodewind{}
 
 // ReconcileCodewind reconciles a Codewind object
 type ReconcileCodewind struct {
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	// Last gatekeeper request count seen by the idle detector for each Codewind instance
+	activityMutex  sync.Mutex
+	activityCounts map[types.UID]float64
 }
 
 // Reconcile reads that state of the cluster for a Codewind object and makes changes based on the state read
@@ -143,9 +213,33 @@ type ReconcileCodewind struct {
 func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
-	isOpenshift, _, err := util.DetectOpenShift()
+
+	// Fetch the Codewind instance
+	codewind := &codewindv1alpha1.Codewind{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, codewind)
 	if err != nil {
-		reqLogger.Error(err, "An error occurred when detecting current infrastructure", "")
+		if k8serr.IsNotFound(err) {
+			//Codewind resource not found. Ignoring since it must be deleted
+			metrics.DeleteCertificateExpiry(request.Namespace, "Codewind", request.Name)
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get Codewind instance")
+		return reconcile.Result{}, err
+	}
+
+	// When watching the whole cluster only namespaces matching the selector are managed, deletions are always
+	// processed so that finalizers are released
+	if codewind.GetDeletionTimestamp() == nil {
+		watched, err := util.NamespaceWatched(r.client, codewind.Namespace)
+		if err != nil {
+			reqLogger.Error(err, "Unable to check the namespace against the watch namespace selector")
+			return reconcile.Result{}, err
+		}
+		if !watched {
+			reqLogger.Info("Ignoring Codewind in a namespace that does not match the watch namespace selector")
+			return reconcile.Result{}, nil
+		}
 	}
 
 	// Fetch the config map
@@ -154,13 +248,40 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: defaults.OperatorConfigMapName, Namespace: operatorNamespace}, operatorConfigMap)
 	if err != nil {
 		reqLogger.Error(err, "Unable to read config map. Ensure one has been created in the same namespace as the operator", "name", defaults.OperatorConfigMapName)
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapUnavailable, "Unable to read the operator config map %s/%s: %v", operatorNamespace, defaults.OperatorConfigMapName, err)
 		return reconcile.Result{}, err
 	}
 
+	// The platform profile supplies the storage, ingress and security defaults, the operator config map may name
+	// a different platform than the one detected at startup
+	profile, err := platform.Current(operatorConfigMap.Data)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring the platform of the operator config map", "Platform", profile.Name)
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid platform in the operator config map: %v", err)
+	}
+
 	codewindConfigMap := OperatorConfigMapCodewind{
-		IngressDomain: operatorConfigMap.Data["ingressDomain"],
-		StorageSize:   operatorConfigMap.Data["storageCodewindSize"],
-		DefaultRealm:  operatorConfigMap.Data["defaultRealm"],
+		IngressDomain:          operatorConfigMap.Data["ingressDomain"],
+		StorageSize:            util.ValueOrDefault(operatorConfigMap.Data["storageCodewindSize"], defaults.CodewindStorageSize),
+		LogLevel:               operatorConfigMap.Data["logLevel"],
+		DefaultRealm:           operatorConfigMap.Data["defaultRealm"],
+		ImagePFE:               operatorConfigMap.Data["imagePFE"],
+		ImagePerformance:       operatorConfigMap.Data["imagePerformance"],
+		ImageGatekeeper:        operatorConfigMap.Data["imageGatekeeper"],
+		ImageAsleep:            operatorConfigMap.Data["imageAsleep"],
+		ImageTag:               operatorConfigMap.Data["imageTag"],
+		ImagePullPolicy:        operatorConfigMap.Data["imagePullPolicy"],
+		ImagePullSecrets:       operatorConfigMap.Data["imagePullSecrets"],
+		PodTemplatePFE:         operatorConfigMap.Data["podTemplatePFE"],
+		PodTemplatePerformance: operatorConfigMap.Data["podTemplatePerformance"],
+		PodTemplateGatekeeper:  operatorConfigMap.Data["podTemplateGatekeeper"],
+		IdleTimeout:            operatorConfigMap.Data["idleTimeout"],
+		IdleActivityMetric:     operatorConfigMap.Data["idleActivityMetric"],
+		CertRenewalWindow:      operatorConfigMap.Data["certRenewalWindow"],
+		CertIssuerName:         operatorConfigMap.Data["certIssuerName"],
+		CertIssuerKind:         operatorConfigMap.Data["certIssuerKind"],
+		HTTPClient:             util.HTTPClientSettingsFromConfigMap(operatorConfigMap.Data),
+		Platform:               profile,
 	}
 
 	// get the operator config map
@@ -171,27 +292,15 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
-	// Use ROKSStorageClass when it is available
-	storageClassName := ""
-	storageClassDef := &storagev1.StorageClass{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: defaults.ROKSStorageClass, Namespace: ""}, storageClassDef)
-	if err == nil {
-		reqLogger.Info("Using storageclass", "name", defaults.ROKSStorageClass)
-		storageClassName = defaults.ROKSStorageClass
-	}
-
-	// Fetch the Codewind instance
-	codewind := &codewindv1alpha1.Codewind{}
-	err = r.client.Get(context.TODO(), request.NamespacedName, codewind)
+	// The defaults of the tenant namespace take precedence over the operator config map
+	tenantDefaults, err := util.GetTenantDefaults(r.client, codewind.Namespace)
 	if err != nil {
-		if k8serr.IsNotFound(err) {
-			//Codewind resource not found. Ignoring since it must be deleted
-			return reconcile.Result{}, nil
-		}
-		// Error reading the object - requeue the request.
-		reqLogger.Error(err, "Failed to get Codewind instance")
+		reqLogger.Error(err, "Unable to read the tenant defaults", "Namespace", codewind.Namespace)
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Unable to read the tenant defaults: %v", err)
 		return reconcile.Result{}, err
 	}
+	ingressDomain := util.ValueOrDefault(tenantDefaults.IngressDomain, codewindConfigMap.IngressDomain)
+	storageClassName := util.ValueOrDefault(tenantDefaults.StorageClassName, profile.CodewindStorageClass)
 
 	// Get the workspaceID from the CR else generate and store a new workspaceID
 	workspaceID := r.getCodewindWorkspaceID(codewind)
@@ -221,20 +330,87 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		CodewindPerformanceServiceName:      defaults.PrefixCodewindPerformance + "-" + workspaceID,
 		CodewindGatekeeperDeploymentName:    defaults.PrefixCodewindGatekeeper + "-" + workspaceID,
 		CodewindGatekeeperIngressName:       defaults.PrefixCodewindGatekeeper + "-" + workspaceID,
-		CodewindGatekeeperIngressHost:       defaults.PrefixCodewindGatekeeper + "-" + workspaceID + "." + codewind.Namespace + "." + codewindConfigMap.IngressDomain,
+		CodewindGatekeeperIngressHost:       defaults.PrefixCodewindGatekeeper + "-" + workspaceID + "." + codewind.Namespace + "." + ingressDomain,
 		CodewindGatekeeperSecretSessionName: "secret-codewind-session-" + workspaceID,
 		CodewindGatekeeperSecretTLSName:     "secret-codewind-tls-" + workspaceID,
 		CodewindGatekeeperTLSCertTitle:      "Codewind" + "-" + workspaceID,
+		CodewindGatekeeperCertificateName:   "codewind-tls-" + workspaceID,
 		CodewindGatekeeperSecretAuthName:    "secret-codewind-client-" + workspaceID,
 		CodewindGatekeeperServiceName:       defaults.PrefixCodewindGatekeeper + "-" + workspaceID,
+		CodewindAsleepName:                  defaults.PrefixCodewindAsleep + "-" + workspaceID,
+		CodewindAsleepImage:                 util.ValueOrDefault(codewindConfigMap.ImageAsleep, defaults.CodewindAsleepImage),
+		ImagePullPolicy:                     util.ResolvePullPolicy(codewind.Spec.ImagePullPolicy, codewindConfigMap.ImagePullPolicy),
+		ImagePullSecrets:                    util.ResolvePullSecrets(codewind.Spec.ImagePullSecrets, codewindConfigMap.ImagePullSecrets),
+		IngressClass:                        profile.IngressClass,
+	}
+
+	// Scale the Codewind deployments to zero while the instance is suspended
+	deploymentOptions.CodewindReplicas = 1
+	deploymentOptions.CodewindState = defaults.CodewindStateRunning
+	if isSuspended(codewind) {
+		deploymentOptions.CodewindReplicas = 0
+		deploymentOptions.CodewindState = defaults.CodewindStateAsleep
+	}
+
+	// Resolve the container images from the CR overrides, then the requested version, then the operator config map,
+	// then the built in defaults
+	codewindImages := codewind.Spec.Images
+	if codewindImages == nil {
+		codewindImages = &codewindv1alpha1.CodewindImages{}
+	}
+	deploymentOptions.CodewindVersion = util.ValueOrDefault(codewind.Spec.Version, util.ValueOrDefault(codewindConfigMap.ImageTag, defaults.CodewindImageTag))
+	deploymentOptions.CodewindPFEImage = util.ResolveImage(codewindImages.PFE,
+		util.ValueOrDefault(codewindConfigMap.ImagePFE, defaults.CodewindImage),
+		util.ValueOrDefault(codewind.Spec.Version, util.ValueOrDefault(codewindConfigMap.ImageTag, defaults.CodewindImageTag)))
+	deploymentOptions.CodewindPerformanceImage = util.ResolveImage(codewindImages.Performance,
+		util.ValueOrDefault(codewindConfigMap.ImagePerformance, defaults.CodewindPerformanceImage),
+		util.ValueOrDefault(codewind.Spec.Version, util.ValueOrDefault(codewindConfigMap.ImageTag, defaults.CodewindPerformanceImageTag)))
+	deploymentOptions.CodewindGatekeeperImage = util.ResolveImage(codewindImages.Gatekeeper,
+		util.ValueOrDefault(codewindConfigMap.ImageGatekeeper, defaults.CodewindGatekeeperImage),
+		util.ValueOrDefault(codewind.Spec.Version, util.ValueOrDefault(codewindConfigMap.ImageTag, defaults.CodewindGatekeeperImageTag)))
+	// Resolve the resource and scheduling settings of each pod from the CR, then the operator config map
+	codewindPodTemplates := codewind.Spec.PodTemplates
+	if codewindPodTemplates == nil {
+		codewindPodTemplates = &codewindv1alpha1.CodewindPodTemplates{}
+	}
+	deploymentOptions.CodewindPFEPodTemplate, err = util.ResolvePodTemplate(codewindPodTemplates.PFE, codewindConfigMap.PodTemplatePFE)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid pod template defaults in the operator config map", "Key", "podTemplatePFE")
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid podTemplatePFE in the operator config map: %v", err)
+	}
+	deploymentOptions.CodewindPerformancePodTemplate, err = util.ResolvePodTemplate(codewindPodTemplates.Performance, codewindConfigMap.PodTemplatePerformance)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid pod template defaults in the operator config map", "Key", "podTemplatePerformance")
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid podTemplatePerformance in the operator config map: %v", err)
+	}
+	deploymentOptions.CodewindGatekeeperPodTemplate, err = util.ResolvePodTemplate(codewindPodTemplates.Gatekeeper, codewindConfigMap.PodTemplateGatekeeper)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid pod template defaults in the operator config map", "Key", "podTemplateGatekeeper")
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid podTemplateGatekeeper in the operator config map: %v", err)
+	}
+
+	codewind.Status.Images = codewindv1alpha1.CodewindStatusImages{
+		PFE:         deploymentOptions.CodewindPFEImage,
+		Performance: deploymentOptions.CodewindPerformanceImage,
+		Gatekeeper:  deploymentOptions.CodewindGatekeeperImage,
 	}
 
 	// Check if Codewind is being deleted
 	if !codewind.GetDeletionTimestamp().IsZero() {
 
+		// Remove the Keycloak registration, then the cluster role bindings, clearing each finalizer as its cleanup completes
+		if hasFinalizer(codewind, defaults.CodewindKeycloakFinalizerName) {
+			result, err := r.handleCodewindKeycloakFinalizer(codewind, deploymentOptions, codewindConfigMap, reqLogger, request)
+			if err != nil || result.Requeue || result.RequeueAfter > 0 {
+				return result, err
+			}
+		}
+
 		// Perform finalizer clean up, then clear the finalizer, then allow this Codewind CR to be deleted
-		if err := r.handleCodewindCRBFinalizer(codewind, deploymentOptions, reqLogger, request); err != nil {
-			return reconcile.Result{}, err
+		if hasFinalizer(codewind, defaults.CodewindFinalizerName) {
+			if err := r.handleCodewindCRBFinalizer(codewind, deploymentOptions, reqLogger, request); err != nil {
+				return reconcile.Result{}, err
+			}
 		}
 
 		//Stop the reconcile
@@ -253,6 +429,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		newClusterRoles := r.clusterRolesForCodewind(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Codewind cluster roles", "Namespace", "", "Name", newClusterRoles.Name)
 		err = r.client.Create(context.TODO(), newClusterRoles)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newClusterRoles, err)
 		if err != nil {
 			reqLogger.Error(err, "Failed to create new Codewind cluster roles.", "Namespace", "", "Name", newClusterRoles.Name)
 			return reconcile.Result{}, err
@@ -269,6 +446,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		newRoleBinding := r.roleBindingForCodewind(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Codewind role binding", "Namespace", newRoleBinding.Namespace, "Name", newRoleBinding.Name)
 		err = r.client.Create(context.TODO(), newRoleBinding)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newRoleBinding, err)
 		if err != nil {
 			reqLogger.Error(err, "Failed to create new Codewind role binding.", "Namespace", newRoleBinding.Namespace, "Name", newRoleBinding.Name)
 			return reconcile.Result{}, err
@@ -285,6 +463,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		newClusterRoles := r.clusterRolesForCodewindTekton(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Codewind Tekton cluster roles", "Namespace", "", "Name", newClusterRoles.Name)
 		err = r.client.Create(context.TODO(), newClusterRoles)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newClusterRoles, err)
 		if err != nil {
 			reqLogger.Error(err, "Failed to create new Codewind Tekton cluster roles.", "Namespace", "", "Name", newClusterRoles.Name)
 			return reconcile.Result{}, err
@@ -301,6 +480,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		newTektonRoleBinding := r.roleBindingForCodewindTekton(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Codewind Tekton ClusterRoleBinding", "Namespace", newTektonRoleBinding.Namespace, "Name", newTektonRoleBinding.Name)
 		err = r.client.Create(context.TODO(), newTektonRoleBinding)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newTektonRoleBinding, err)
 		if err != nil {
 			reqLogger.Error(err, "Failed to create new Codewind Tekton ClusterRoleBinding.", "Namespace", newTektonRoleBinding.Namespace, "Name", newTektonRoleBinding.Name)
 			return reconcile.Result{}, err
@@ -310,7 +490,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
-	if isOpenshift {
+	if profile.OpenShift {
 		// Check if the ODO Cluster roles already exist, if not create new ones
 		clusterRolesODO := &rbacv1.ClusterRole{}
 		err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindODOClusterRolesName, Namespace: ""}, clusterRolesODO)
@@ -318,6 +498,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 			newClusterRoles := r.clusterRolesForCodewindODO(codewind, deploymentOptions)
 			reqLogger.Info("Creating new Codewind ODO cluster roles", "Name", newClusterRoles.Name)
 			err = r.client.Create(context.TODO(), newClusterRoles)
+			util.RecordCreate(r.recorder, r.scheme, codewind, newClusterRoles, err)
 			if err != nil {
 				reqLogger.Error(err, "Failed to create new Codewind ODO cluster roles.", "Name", newClusterRoles.Name)
 				return reconcile.Result{}, err
@@ -334,6 +515,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 			newODORoleBinding := r.roleBindingForCodewindODO(codewind, deploymentOptions)
 			reqLogger.Info("Creating a new Codewind ODO ClusterRoleBinding", "ServiceAccount", newODORoleBinding.Namespace+":"+deploymentOptions.CodewindServiceAccountName, "Name", newODORoleBinding.Name)
 			err = r.client.Create(context.TODO(), newODORoleBinding)
+			util.RecordCreate(r.recorder, r.scheme, codewind, newODORoleBinding, err)
 			if err != nil {
 				reqLogger.Error(err, "Failed to create new Codewind ODO ClusterRoleBinding.", "ServiceAccount", newODORoleBinding.Namespace+":"+deploymentOptions.CodewindServiceAccountName, "Name", newODORoleBinding.Name)
 				return reconcile.Result{}, err
@@ -351,6 +533,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		newServiceAccount := r.serviceAccountForCodewind(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Codewind service account", "Namespace", newServiceAccount.Namespace, "Name", newServiceAccount.Name)
 		err = r.client.Create(context.TODO(), newServiceAccount)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newServiceAccount, err)
 		if err != nil {
 			reqLogger.Error(err, "Failed to create new Codewind service account.", "Namespace", newServiceAccount.Namespace, "Name", newServiceAccount.Name)
 			return reconcile.Result{}, err
@@ -360,45 +543,64 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
-	// Check if the Codewind PVC already exist, if not create a new one
+	// Check if the Codewind PVC already exist, if not create a new one. An existing PVC is expanded when the
+	// requested size grows
+	storageSize := util.ValueOrDefault(codewind.Spec.StorageSize, codewindConfigMap.StorageSize)
 	codewindPVC := &corev1.PersistentVolumeClaim{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindPFEPVCName, Namespace: codewind.Namespace}, codewindPVC)
 	if err != nil && k8serr.IsNotFound(err) {
 		newCodewindPVC := r.pvcForCodewind(codewind, deploymentOptions, storageClassName, codewindConfigMap.StorageSize)
 		reqLogger.Info("Creating a new Codewind PFE PVC", "Namespace", newCodewindPVC.Namespace, "Name", newCodewindPVC.Name)
 		err = r.client.Create(context.TODO(), newCodewindPVC)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newCodewindPVC, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new PFE PVC.", "Namespace", newCodewindPVC.Namespace, "Name", newCodewindPVC.Name)
 			return reconcile.Result{}, err
 		}
+		setStorageCondition(codewind, newCodewindPVC)
+		codewind.Status.Storage = util.NewPVCStorageStatus(newCodewindPVC)
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get PFE PVC.")
 		return reconcile.Result{}, err
+	} else {
+		setStorageCondition(codewind, codewindPVC)
+		storage, expanded, err := util.ExpandPVC(r.client, codewindPVC, storageSize)
+		if storage != nil {
+			util.RecordStorage(r.recorder, codewind, codewind.Status.Storage, storage, expanded)
+			codewind.Status.Storage = storage
+		}
+		if err != nil {
+			reqLogger.Error(err, "Failed to expand PFE PVC.", "Namespace", codewindPVC.Namespace, "Name", codewindPVC.Name, "Size", storageSize)
+			return reconcile.Result{}, err
+		}
+		if expanded {
+			reqLogger.Info("Expanding PFE PVC", "Namespace", codewindPVC.Namespace, "Name", codewindPVC.Name, "Size", storageSize)
+		}
 	}
 
-	keycloakPod, err := r.getKeycloakPod(reqLogger, request, codewind.Spec.KeycloakDeployment)
-	if err != nil || keycloakPod == nil {
-		reqLogger.Error(err, "Unable to find the requested Keycloak pod")
-		return reconcile.Result{RequeueAfter: time.Second * 10}, err
-	}
-	reqLogger.Info("Found the running Keycloak Pod", "Labels:", keycloakPod.GetLabels())
-
-	// Get the keycloak admin credentials
-	authID := keycloakPod.GetLabels()["authID"]
-	if authID == "" {
-		reqLogger.Error(err, "Unable to find AuthID in keycloak pod.", "Namespace", keycloakPod.Namespace, "Name", keycloakPod.Name)
-		return reconcile.Result{}, err
+	// Sign the generated certificates with the operator CA and publish the CA to PFE
+	ca, err := util.EnsureCertificateAuthority(r.client, defaults.OperatorCASecretName, defaults.CABundleConfigMapName)
+	if err != nil {
+		reqLogger.Error(err, "Unable to load the operator CA, using self-signed certificates")
+		ca = nil
+	} else if err = r.publishCABundle(codewind, ca); err != nil {
+		reqLogger.Error(err, "Failed to publish the CA bundle", "Namespace", codewind.Namespace, "Name", defaults.CABundleConfigMapName)
 	}
 
-	keycloakAdminUser, keycloakAdminPass, err := r.getKeycloakAdminCredentials(authID, keycloakPod.Namespace)
+	keycloak, reason, err := r.getKeycloakConnection(reqLogger, request, codewind, codewindConfigMap)
 	if err != nil {
-		reqLogger.Error(err, "Unable to retrieve the Keycloak credentials")
+		reqLogger.Error(err, "Unable to connect to the requested Keycloak")
+		r.recorder.Event(codewind, corev1.EventTypeWarning, reason, err.Error())
+		setCondition(codewind, codewindv1alpha1.CodewindConditionKeycloakRegistered, corev1.ConditionFalse, reason, err.Error())
+		if statusErr := r.updateCodewindStatus(codewind); statusErr != nil {
+			reqLogger.Error(statusErr, "Failed to update Codewind status")
+		}
 		return reconcile.Result{RequeueAfter: time.Second * 10}, err
 	}
 
-	keycloakRealm := codewindConfigMap.DefaultRealm
-	keycloakAuthHostName := defaults.PrefixCodewindKeycloak + "-" + authID + "." + keycloakPod.Namespace + "." + codewindConfigMap.IngressDomain
-	keycloakAuthURL := "https://" + keycloakAuthHostName
+	keycloakRealm := keycloak.Realm
+	keycloakAuthHostName := keycloak.AuthHost
+	keycloakAuthURL := keycloak.AuthURL
 	keycloakClientID := "codewind-" + deploymentOptions.WorkspaceID
 	gatekeeperPublicURL := "https://" + deploymentOptions.CodewindGatekeeperIngressHost
 	clientKey := ""
@@ -406,23 +608,33 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 	// Update Keycloak for user if needed
 	if codewind.Status.KeycloakStatus == "" {
 		codewind.Status.KeycloakStatus = defaults.ConstKeycloakConfigStarted
-		clientKey, err = security.AddCodewindToKeycloak(deploymentOptions.WorkspaceID, keycloakAuthURL, keycloakRealm, keycloakAdminUser, keycloakAdminPass, gatekeeperPublicURL, codewind.Spec.Username, keycloakClientID)
+		r.recorder.Eventf(codewind, corev1.EventTypeNormal, eventReasonRegistering, "Registering client %s in realm %s of %s", keycloakClientID, keycloakRealm, keycloakAuthURL)
+		clientKey, err = security.AddCodewindToKeycloak(keycloak.HTTPClient, deploymentOptions.WorkspaceID, keycloakAuthURL, keycloakRealm, keycloak.AdminUser, keycloak.AdminPass, gatekeeperPublicURL, codewind.Spec.Username, keycloakClientID)
 		if err != nil {
 			reqLogger.Error(err, "Failed to update Keycloak for deployment.", "Namespace", codewind.Namespace, "ClientID", keycloakClientID)
+			codewind.Status.KeycloakStatus = ""
+			r.recorder.Eventf(codewind, corev1.EventTypeWarning, reasonRegistrationFailed, "Failed to register client %s: %v", keycloakClientID, err)
+			setCondition(codewind, codewindv1alpha1.CodewindConditionKeycloakRegistered, corev1.ConditionFalse, reasonRegistrationFailed, "Failed to register client "+keycloakClientID+": "+err.Error())
+			if statusErr := r.updateCodewindStatus(codewind); statusErr != nil {
+				reqLogger.Error(statusErr, "Failed to update Codewind status")
+			}
 			return reconcile.Result{}, err
 		}
 		codewind.Status.KeycloakStatus = defaults.ConstKeycloakConfigReady
+		r.recorder.Eventf(codewind, corev1.EventTypeNormal, reasonRegistered, "Registered client %s in realm %s", keycloakClientID, keycloakRealm)
 	}
+	setCondition(codewind, codewindv1alpha1.CodewindConditionKeycloakRegistered, corev1.ConditionTrue, reasonRegistered, "Registered client "+keycloakClientID+" in realm "+keycloakRealm)
 
 	// Check if the Codewind PFE Deployment already exists, if not create a new one
+	// Define the required Deployment
+	dep := r.deploymentForCodewindPFE(codewind, deploymentOptions, profile.OpenShift, keycloakRealm, keycloakAuthHostName, util.ValueOrDefault(codewind.Spec.LogLevel, codewindConfigMap.LogLevel), ingressDomain)
 	deployment := &appsv1.Deployment{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindPFEDeploymentName, Namespace: codewind.Namespace}, deployment)
 	if err != nil && k8serr.IsNotFound(err) {
-		// Define a new Deployment
-		dep := r.deploymentForCodewindPFE(codewind, deploymentOptions, isOpenshift, keycloakRealm, keycloakAuthHostName, codewind.Spec.LogLevel, codewindConfigMap.IngressDomain)
 		reqLogger.Info("The workspace ID of this is:", "WorkspaceID", deploymentOptions.WorkspaceID)
 		reqLogger.Info("Creating a new PFE Deployment.", "Namespace", dep.Namespace, "Name", dep.Name)
 		err = r.client.Create(context.TODO(), dep)
+		util.RecordCreate(r.recorder, r.scheme, codewind, dep, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new PFE deployment.", "Namespace", dep.Namespace, "Name", dep.Name)
 			return reconcile.Result{}, err
@@ -433,14 +645,24 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		reqLogger.Error(err, "Failed to get PFE Deployment.")
 		return reconcile.Result{}, err
 	}
+	setDeploymentCondition(codewind, codewindv1alpha1.CodewindConditionPFEReady, deployment)
+	if util.MergeDeployment(deployment, dep) {
+		reqLogger.Info("Updating PFE Deployment to match the required spec.", "Namespace", deployment.Namespace, "Name", deployment.Name)
+		err = r.client.Update(context.TODO(), deployment)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update PFE Deployment.", "Namespace", deployment.Namespace, "Name", deployment.Name)
+			return reconcile.Result{}, err
+		}
+	}
 
 	// Check if the Codewind PFE Service already exists, if not create a new one
+	newService := r.serviceForCodewindPFE(codewind, deploymentOptions)
 	service := &corev1.Service{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindPFEServiceName, Namespace: codewind.Namespace}, service)
 	if err != nil && k8serr.IsNotFound(err) {
-		newService := r.serviceForCodewindPFE(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Service", "Namespace", newService.Namespace, "Name", newService.Name)
 		err = r.client.Create(context.TODO(), newService)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newService, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new service.", "Namespace", newService.Namespace, "Name", newService.Name)
 			return reconcile.Result{}, err
@@ -448,16 +670,24 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get Service.")
 		return reconcile.Result{}, err
+	} else if util.MergeService(service, newService) {
+		reqLogger.Info("Updating PFE Service to match the required spec.", "Namespace", service.Namespace, "Name", service.Name)
+		err = r.client.Update(context.TODO(), service)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update PFE Service.", "Namespace", service.Namespace, "Name", service.Name)
+			return reconcile.Result{}, err
+		}
 	}
 
 	// Check if the Codewind Performance Deployment already exists, if not create a new one
+	// Define the required Performance Deployment
+	newDeployment := r.deploymentForCodewindPerformance(codewind, deploymentOptions, ingressDomain)
 	deploymentPerformance := &appsv1.Deployment{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindPerformanceDeploymentName, Namespace: codewind.Namespace}, deploymentPerformance)
 	if err != nil && k8serr.IsNotFound(err) {
-		// Define a new Performance Deployment
-		newDeployment := r.deploymentForCodewindPerformance(codewind, deploymentOptions, codewindConfigMap.IngressDomain)
 		reqLogger.Info("Creating a new Performance deployment.", "Namespace", codewind.Namespace, "Name", newDeployment.Name)
 		err = r.client.Create(context.TODO(), newDeployment)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newDeployment, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Performance deployment.", "Namespace", codewind.Namespace, "Name", newDeployment.Name)
 			return reconcile.Result{}, err
@@ -466,15 +696,23 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get Codewind Performance deployment")
 		return reconcile.Result{}, err
+	} else if util.MergeDeployment(deploymentPerformance, newDeployment) {
+		reqLogger.Info("Updating Performance deployment to match the required spec.", "Namespace", codewind.Namespace, "Name", deploymentPerformance.Name)
+		err = r.client.Update(context.TODO(), deploymentPerformance)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update Performance deployment.", "Namespace", codewind.Namespace, "Name", deploymentPerformance.Name)
+			return reconcile.Result{}, err
+		}
 	}
 
 	// Check if the Codewind Performance Service already exists, if not create a new one
+	newService = r.serviceForCodewindPerformance(codewind, deploymentOptions)
 	servicePerformance := &corev1.Service{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindPerformanceServiceName, Namespace: codewind.Namespace}, servicePerformance)
 	if err != nil && k8serr.IsNotFound(err) {
-		newService := r.serviceForCodewindPerformance(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Codewind performance service", "Namespace", newService.Namespace, "Name", newService.Name)
 		err = r.client.Create(context.TODO(), newService)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newService, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Service.", "Namespace", newService.Namespace, "Name", newService.Name)
 			return reconcile.Result{}, err
@@ -482,6 +720,13 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get Codewind Performance service")
 		return reconcile.Result{}, err
+	} else if util.MergeService(servicePerformance, newService) {
+		reqLogger.Info("Updating Codewind performance service to match the required spec.", "Namespace", servicePerformance.Namespace, "Name", servicePerformance.Name)
+		err = r.client.Update(context.TODO(), servicePerformance)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update Codewind performance service.", "Namespace", servicePerformance.Namespace, "Name", servicePerformance.Name)
+			return reconcile.Result{}, err
+		}
 	}
 
 	// Check if the Codewind Gatekeeper session secrets already exist, if not create new ones
@@ -493,6 +738,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		newSecret := r.buildGatekeeperSecretSession(codewind, deploymentOptions, session)
 		reqLogger.Info("Creating a new Secret", "Namespace", newSecret.Namespace, "Name", newSecret.Name)
 		err = r.client.Create(context.TODO(), newSecret)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newSecret, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Gatekeeper session secret.", "Namespace", newSecret.Namespace, "Name", newSecret.Name)
 			return reconcile.Result{}, err
@@ -503,20 +749,65 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 	}
 
 	// Check if the Codewind Gatekeeper TLS secrets already exist, if not create new ones
+	renewalWindow, err := util.ResolveRenewalWindow(codewindConfigMap.CertRenewalWindow, defaults.CertificateRenewalWindow)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid certRenewalWindow in the operator config map", "Value", codewindConfigMap.CertRenewalWindow)
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid certRenewalWindow %q in the operator config map", codewindConfigMap.CertRenewalWindow)
+	}
+	// When a cert-manager issuer is set the TLS secret is issued by cert-manager instead
+	issuer := util.ResolveIssuer(codewind.Spec.IssuerRef, codewindConfigMap.CertIssuerName, codewindConfigMap.CertIssuerKind)
+	certManaged, err := r.reconcileGatekeeperCertificate(reqLogger, codewind, deploymentOptions, issuer, renewalWindow)
+	if err != nil {
+		if issuer != nil {
+			reqLogger.Error(err, "Unable to request the Gatekeeper certificate from cert-manager, using a self-signed certificate", "Issuer", issuer.Name)
+		} else {
+			reqLogger.Error(err, "Unable to remove the Gatekeeper Certificate", "Namespace", codewind.Namespace, "Name", deploymentOptions.CodewindGatekeeperCertificateName)
+		}
+	}
+	certificateWait := time.Duration(0)
 	secret = &corev1.Secret{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindGatekeeperSecretTLSName, Namespace: codewind.Namespace}, secret)
-	if err != nil && k8serr.IsNotFound(err) {
+	if err != nil && k8serr.IsNotFound(err) && certManaged {
+		// The gatekeeper starts once cert-manager has issued the secret
+		reqLogger.Info("Waiting for cert-manager to issue the Gatekeeper TLS secret", "Namespace", codewind.Namespace, "Name", deploymentOptions.CodewindGatekeeperSecretTLSName)
+		secret = nil
+		certificateWait = defaults.CertificateIssueRetryInterval
+	} else if err != nil && k8serr.IsNotFound(err) {
 		// Define a new Secrets object
-		newSecret := r.buildGatekeeperSecretTLS(codewind, deploymentOptions, codewindConfigMap.IngressDomain)
+		newSecret := r.buildGatekeeperSecretTLS(codewind, deploymentOptions, ingressDomain, ca)
 		reqLogger.Info("Creating a new Secret", "Namespace", newSecret.Namespace, "Name", newSecret.Name)
 		err = r.client.Create(context.TODO(), newSecret)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newSecret, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Gatekeeper TLS secret.", "Namespace", newSecret.Namespace, "Name", newSecret.Name)
 			return reconcile.Result{}, err
 		}
+		secret = newSecret
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get TLS secret.")
 		return reconcile.Result{}, err
+	} else if !certManaged {
+		// Re-issue the certificate once it enters the renewal window or when it is not signed by the operator CA, the
+		// gatekeeper restarts to load it
+		renewed, _, err := util.RenewCertificateIfDue(secret, deploymentOptions.CodewindGatekeeperIngressHost, deploymentOptions.CodewindGatekeeperTLSCertTitle, renewalWindow, ca)
+		if err != nil {
+			reqLogger.Error(err, "Failed to renew the Gatekeeper TLS certificate.", "Namespace", secret.Namespace, "Name", secret.Name)
+			return reconcile.Result{}, err
+		}
+		if renewed {
+			reqLogger.Info("Renewing the Gatekeeper TLS certificate", "Namespace", secret.Namespace, "Name", secret.Name)
+			err = r.client.Update(context.TODO(), secret)
+			if err != nil {
+				reqLogger.Error(err, "Failed to update Gatekeeper TLS secret.", "Namespace", secret.Namespace, "Name", secret.Name)
+				return reconcile.Result{}, err
+			}
+			r.recorder.Eventf(codewind, corev1.EventTypeNormal, eventReasonCertificateRenewed, "Renewed the gatekeeper TLS certificate in secret %s", secret.Name)
+		}
+	}
+	if secret != nil {
+		certificateWait = trackCertificateExpiry(reqLogger, codewind, &deploymentOptions, secret, renewalWindow)
+		deploymentOptions.CodewindGatekeeperTLSCert = util.SecretValue(secret, "tls.crt")
+		deploymentOptions.CodewindGatekeeperTLSKey = util.SecretValue(secret, "tls.key")
 	}
 
 	// Check if the Codewind Gatekeeper Auth secrets already exist, if not create new ones
@@ -527,6 +818,7 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		newSecret := r.buildGatekeeperSecretAuth(codewind, deploymentOptions, clientKey)
 		reqLogger.Info("Creating a new Gatekeeper Auth Secret", "Namespace", newSecret.Namespace, "Name", newSecret.Name)
 		err = r.client.Create(context.TODO(), newSecret)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newSecret, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Gatekeeper TLS secret.", "Namespace", newSecret.Namespace, "Name", newSecret.Name)
 			return reconcile.Result{}, err
@@ -536,14 +828,26 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
+	// Regenerate the client secret when a rotation is requested on this instance or on its Keycloak
+	rotationWait, err := r.rotateCodewindCredentials(reqLogger, codewind, deploymentOptions, keycloak, keycloakClientID)
+	if err != nil {
+		reqLogger.Error(err, "Failed to rotate the Codewind client secret", "Namespace", codewind.Namespace, "ClientID", keycloakClientID)
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonRotationFailed, "Failed to rotate the secret of client %s: %v", keycloakClientID, err)
+		rotationWait = defaults.CredentialRotationRetryInterval
+	}
+	if codewind.Status.CredentialsRotatedAt != nil {
+		deploymentOptions.CodewindCredentialsRotatedAt = codewind.Status.CredentialsRotatedAt.UTC().Format(time.RFC3339)
+	}
+
 	// Check if the Codewind Gatekeeper Deployment already exists, if not create a new one
+	// Define the required Gatekeeper Deployment
+	newDeployment = r.deploymentForCodewindGatekeeper(codewind, deploymentOptions, profile.OpenShift, keycloakRealm, keycloakClientID, keycloakAuthURL, ingressDomain)
 	deploymentGatekeeper := &appsv1.Deployment{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindGatekeeperDeploymentName, Namespace: codewind.Namespace}, deploymentGatekeeper)
 	if err != nil && k8serr.IsNotFound(err) {
-		// Define a new Gatekeeper Deployment
-		newDeployment := r.deploymentForCodewindGatekeeper(codewind, deploymentOptions, isOpenshift, keycloakRealm, keycloakClientID, keycloakAuthURL, codewindConfigMap.IngressDomain)
 		reqLogger.Info("Creating a new Gatekeeper deployment.", "Namespace", codewind.Namespace, "Name", newDeployment.Name)
 		err = r.client.Create(context.TODO(), newDeployment)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newDeployment, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Gatekeeper deployment.", "Namespace", codewind.Namespace, "Name", newDeployment.Name)
 			return reconcile.Result{}, err
@@ -553,14 +857,57 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 		reqLogger.Error(err, "Failed to get Codewind Gatekeeper deployment")
 		return reconcile.Result{}, err
 	}
+	setDeploymentCondition(codewind, codewindv1alpha1.CodewindConditionGatekeeperReady, deploymentGatekeeper)
+	if util.MergeDeployment(deploymentGatekeeper, newDeployment) {
+		reqLogger.Info("Updating Gatekeeper deployment to match the required spec.", "Namespace", codewind.Namespace, "Name", deploymentGatekeeper.Name)
+		err = r.client.Update(context.TODO(), deploymentGatekeeper)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update Gatekeeper deployment.", "Namespace", codewind.Namespace, "Name", deploymentGatekeeper.Name)
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Track the rollout of the requested version across the Codewind deployments
+	trackCodewindUpgrade(reqLogger, codewind, deploymentOptions.CodewindVersion, deployment, deploymentPerformance, deploymentGatekeeper)
+
+	// Suspend the instance once the gatekeeper has been idle for longer than the idle timeout. The suspension is
+	// recorded in an annotation so that spec.suspended remains under the control of the user
+	result := reconcile.Result{}
+	idleTimeout := r.resolveIdleTimeout(reqLogger, codewind, codewindConfigMap.IdleTimeout)
+	activityMetric := util.ValueOrDefault(codewindConfigMap.IdleActivityMetric, defaults.GatekeeperActivityMetric)
+	if r.checkIdleTimeout(reqLogger, codewind, deploymentOptions, activityMetric, idleTimeout) {
+		reqLogger.Info("Suspending idle Codewind instance", "Namespace", codewind.Namespace, "Name", codewind.Name, "IdleTimeout", idleTimeout.String())
+		annotations := codewind.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[defaults.CodewindIdleSuspendedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		codewind.SetAnnotations(annotations)
+		err = r.client.Update(context.TODO(), codewind)
+		if err != nil {
+			reqLogger.Error(err, "Failed to suspend idle Codewind instance", "Namespace", codewind.Namespace, "Name", codewind.Name)
+			return reconcile.Result{}, err
+		}
+		r.recorder.Eventf(codewind, corev1.EventTypeNormal, reasonIdleSuspended, "Suspended after %s without gatekeeper activity", idleTimeout.String())
+		return reconcile.Result{Requeue: true}, nil
+	}
+	if idleTimeout > 0 && !isSuspended(codewind) {
+		result.RequeueAfter = defaults.IdleCheckInterval
+	}
+	for _, wait := range []time.Duration{rotationWait, certificateWait} {
+		if wait > 0 && (result.RequeueAfter == 0 || wait < result.RequeueAfter) {
+			result.RequeueAfter = wait
+		}
+	}
 
 	// Check if the Codewind Gatekeeper Service already exists, if not create a new one
+	newService = r.serviceForCodewindGatekeeper(codewind, deploymentOptions)
 	serviceGatekeeper := &corev1.Service{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: defaults.PrefixCodewindGatekeeper + "-" + deploymentOptions.WorkspaceID, Namespace: codewind.Namespace}, serviceGatekeeper)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindGatekeeperServiceName, Namespace: codewind.Namespace}, serviceGatekeeper)
 	if err != nil && k8serr.IsNotFound(err) {
-		newService := r.serviceForCodewindGatekeeper(codewind, deploymentOptions)
 		reqLogger.Info("Creating a new Codewind gatekeeper Service", "Namespace", newService.Namespace, "Name", newService.Name)
 		err = r.client.Create(context.TODO(), newService)
+		util.RecordCreate(r.recorder, r.scheme, codewind, newService, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Codewind gatekeeper service.", "Namespace", newService.Namespace, "Name", newService.Name)
 			return reconcile.Result{}, err
@@ -568,70 +915,100 @@ func (r *ReconcileCodewind) Reconcile(request reconcile.Request) (reconcile.Resu
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get Service.")
 		return reconcile.Result{}, err
+	} else if util.MergeService(serviceGatekeeper, newService) {
+		reqLogger.Info("Updating Codewind gatekeeper Service to match the required spec.", "Namespace", serviceGatekeeper.Namespace, "Name", serviceGatekeeper.Name)
+		err = r.client.Update(context.TODO(), serviceGatekeeper)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update Codewind gatekeeper service.", "Namespace", serviceGatekeeper.Namespace, "Name", serviceGatekeeper.Name)
+			return reconcile.Result{}, err
+		}
 	}
 
-	if isOpenshift {
+	// Serve the asleep page on the gatekeeper host while the instance is suspended
+	err = r.reconcileAsleepBackend(reqLogger, codewind, deploymentOptions)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if profile.OpenShift {
 		// Check if the Codewind Gatekeeper Route already exists, if not create a new one
+		newRoute := r.routeForCodewindGatekeeper(codewind, deploymentOptions, ingressDomain)
 		routeGatekeeper := &routev1.Route{}
 		err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindGatekeeperIngressName, Namespace: codewind.Namespace}, routeGatekeeper)
 		if err != nil && k8serr.IsNotFound(err) {
-			newRoute := r.routeForCodewindGatekeeper(codewind, deploymentOptions, codewindConfigMap.IngressDomain)
 			reqLogger.Info("Creating a new Codewind gatekeeper route", "Namespace", newRoute.Namespace, "Name", newRoute.Name)
 			err = r.client.Create(context.TODO(), newRoute)
+			util.RecordCreate(r.recorder, r.scheme, codewind, newRoute, err)
 			if err != nil && !k8serr.IsAlreadyExists(err) {
 				reqLogger.Error(err, "Failed to create new Codewind gatekeeper route.", "Namespace", newRoute.Namespace, "Name", newRoute.Name)
 				return reconcile.Result{}, err
 			}
-			// Success, update the accessURL
-			codewind.Status.AccessURL = gatekeeperPublicURL
 		} else if err != nil {
 			reqLogger.Error(err, "Failed to get Codewind gatekeeper route")
 			return reconcile.Result{}, err
+		} else if util.MergeRoute(routeGatekeeper, newRoute) {
+			reqLogger.Info("Updating Codewind gatekeeper route to match the required spec.", "Namespace", routeGatekeeper.Namespace, "Name", routeGatekeeper.Name)
+			err = r.client.Update(context.TODO(), routeGatekeeper)
+			if err != nil {
+				reqLogger.Error(err, "Failed to update Codewind gatekeeper route.", "Namespace", routeGatekeeper.Namespace, "Name", routeGatekeeper.Name)
+				return reconcile.Result{}, err
+			}
 		}
-		err = r.client.Status().Update(context.TODO(), codewind)
+		// Update the accessURL to match the current ingress host
+		codewind.Status.AccessURL = gatekeeperPublicURL
+		err = r.updateCodewindStatus(codewind)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 	} else {
 		// Check if the Codewind Gatekeeper Ingress already exists, if not create a new one
+		newIngress := r.ingressForCodewindGatekeeper(codewind, deploymentOptions, ingressDomain)
 		ingressGatekeeper := &extv1beta1.Ingress{}
 		err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindGatekeeperIngressName, Namespace: codewind.Namespace}, ingressGatekeeper)
 		if err != nil && k8serr.IsNotFound(err) {
-			newIngress := r.ingressForCodewindGatekeeper(codewind, deploymentOptions, codewindConfigMap.IngressDomain)
 			reqLogger.Info("Creating a new Codewind gatekeeper ingress", "Namespace", newIngress.Namespace, "Name", newIngress.Name)
 			err = r.client.Create(context.TODO(), newIngress)
+			util.RecordCreate(r.recorder, r.scheme, codewind, newIngress, err)
 			if err != nil && !k8serr.IsAlreadyExists(err) {
 				reqLogger.Error(err, "Failed to create new Codewind gatekeeper ingress.", "Namespace", newIngress.Namespace, "Name", newIngress.Name)
 				return reconcile.Result{}, err
 			}
-			// Success, update the accessURL
-			codewind.Status.AccessURL = gatekeeperPublicURL
 		} else if err != nil {
 			reqLogger.Error(err, "Failed to get Codewind gatekeeper ingress")
 			return reconcile.Result{}, err
+		} else if util.MergeIngress(ingressGatekeeper, newIngress) {
+			reqLogger.Info("Updating Codewind gatekeeper ingress to match the required spec.", "Namespace", ingressGatekeeper.Namespace, "Name", ingressGatekeeper.Name)
+			err = r.client.Update(context.TODO(), ingressGatekeeper)
+			if err != nil {
+				reqLogger.Error(err, "Failed to update Codewind gatekeeper ingress.", "Namespace", ingressGatekeeper.Namespace, "Name", ingressGatekeeper.Name)
+				return reconcile.Result{}, err
+			}
 		}
+		// Update the accessURL to match the current ingress host
+		codewind.Status.AccessURL = gatekeeperPublicURL
 
-		err = r.client.Status().Update(context.TODO(), codewind)
+		err = r.updateCodewindStatus(codewind)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
-func (r *ReconcileCodewind) getKeycloakPod(reqLogger logr.Logger, request reconcile.Request, authName string) (*corev1.Pod, error) {
+func (r *ReconcileCodewind) getKeycloakPod(reqLogger logr.Logger, request reconcile.Request, keycloak types.NamespacedName) (*corev1.Pod, error) {
 	keycloaks := &corev1.PodList{}
 	opts := []client.ListOption{
-		client.MatchingLabels{"app": defaults.PrefixCodewindKeycloak, "authName": authName},
+		client.InNamespace(keycloak.Namespace),
+		client.MatchingLabels{"app": defaults.PrefixCodewindKeycloak, "authName": keycloak.Name},
 	}
 	err := r.client.List(context.TODO(), keycloaks, opts...)
 	if len(keycloaks.Items) == 0 {
-		err = fmt.Errorf("Unable to find Keycloak authName:'%s'", authName)
+		err = fmt.Errorf("Unable to find Keycloak authName:'%s' in namespace '%s'", keycloak.Name, keycloak.Namespace)
 		return nil, err
 	}
-	keycloakPod := keycloaks.Items[0]
-	return &keycloakPod, nil
+	// Any replica can serve the admin API, prefer one that is ready
+	return util.SelectPod(keycloaks.Items), nil
 }
 
 // getKeycloakAdminCredentials from the keycloak secret
@@ -645,17 +1022,17 @@ func (r *ReconcileCodewind) getKeycloakAdminCredentials(authID string, keycloakN
 }
 
 func (r *ReconcileCodewind) getCodewindWorkspaceID(codewind *codewindv1alpha1.Codewind) string {
-	workspaceID := codewind.GetAnnotations()["codewindWorkspace"]
+	workspaceID := codewind.GetAnnotations()[defaults.CodewindWorkspaceAnnotation]
 	return workspaceID
 }
 
 func (r *ReconcileCodewind) setCodewindWorkspaceID(codewind *codewindv1alpha1.Codewind) (string, error) {
-	newWorkspaceID := strings.ToLower(strconv.FormatInt(util.CreateTimestamp(), 36) + util.GenerateRandomString(4))
+	newWorkspaceID := util.GenerateInstanceID()
 	annotations := codewind.GetAnnotations()
 	if annotations == nil {
 		annotations = make(map[string]string)
 	}
-	annotations["codewindWorkspace"] = newWorkspaceID
+	annotations[defaults.CodewindWorkspaceAnnotation] = newWorkspaceID
 	codewind.SetAnnotations(annotations)
 	err := r.client.Update(context.TODO(), codewind)
 	if err != nil {
@@ -0,0 +1,27 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package codewind
+
+// Reasons of the events recorded on Codewind instances, in addition to the status condition reasons and those shared
+// with the Keycloak controller in util
+const (
+	eventReasonConfigMapUnavailable = "ConfigMapUnavailable"
+	eventReasonConfigMapInvalid     = "ConfigMapInvalid"
+	eventReasonRegistering          = "Registering"
+	eventReasonCleanupSkipped       = "CleanupSkipped"
+	eventReasonCleanupFailed        = "CleanupFailed"
+	eventReasonCleanupAbandoned     = "CleanupAbandoned"
+	eventReasonCleanupCompleted     = "CleanupCompleted"
+	eventReasonCredentialsRotated   = "CredentialsRotated"
+	eventReasonRotationFailed       = "CredentialsRotationFailed"
+	eventReasonCertificateRenewed   = "CertificateRenewed"
+)
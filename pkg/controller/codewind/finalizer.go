@@ -13,33 +13,63 @@ package codewind
 
 import (
 	"context"
+	"strconv"
 
 	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
 	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/security"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-// addCodewindFinalizer : Adds the finalizer to the metadata of the Codewind CR
+// addCodewindFinalizer : Adds the finalizers to the metadata of the Codewind CR
 func (r *ReconcileCodewind) addCodewindFinalizer(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, request reconcile.Request) error {
-	if len(codewind.GetFinalizers()) < 1 && codewind.GetDeletionTimestamp() == nil {
-		reqLogger.Info("Adding Finalizer to Codewind", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindFinalizerName)
-		codewind.SetFinalizers([]string{defaults.CodewindFinalizerName})
+	if codewind.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	finalizers := codewind.GetFinalizers()
+	updated := false
+	for _, finalizer := range []string{defaults.CodewindFinalizerName, defaults.CodewindKeycloakFinalizerName} {
+		if !hasFinalizer(codewind, finalizer) {
+			reqLogger.Info("Adding Finalizer to Codewind", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", finalizer)
+			finalizers = append(finalizers, finalizer)
+			updated = true
+		}
+	}
+	if updated {
+		codewind.SetFinalizers(finalizers)
 		err := r.client.Update(context.TODO(), codewind)
 		if err != nil {
-			reqLogger.Error(err, "Failed to update Codewind with the CRB finalizer", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindFinalizerName)
+			reqLogger.Error(err, "Failed to update Codewind with the finalizers", "namespace", codewind.Namespace, "name", codewind.Name)
 			return err
 		}
 	}
 	return nil
 }
 
-// removeFinalizers  : Removes all the finalizers from the Codewind CR
-func (r *ReconcileCodewind) removeFinalizers(codewind *codewindv1alpha1.Codewind) error {
-	codewind.SetFinalizers(nil)
+// hasFinalizer : Checks whether the Codewind CR carries the named finalizer
+func hasFinalizer(codewind *codewindv1alpha1.Codewind, name string) bool {
+	for _, finalizer := range codewind.GetFinalizers() {
+		if finalizer == name {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFinalizer  : Removes the named finalizer from the Codewind CR
+func (r *ReconcileCodewind) removeFinalizer(codewind *codewindv1alpha1.Codewind, name string) error {
+	var finalizers []string
+	for _, finalizer := range codewind.GetFinalizers() {
+		if finalizer != name {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	codewind.SetFinalizers(finalizers)
 	err := r.client.Update(context.TODO(), codewind)
 	if err != nil {
 		return err
@@ -80,12 +110,67 @@ func (r *ReconcileCodewind) handleCodewindCRBFinalizer(codewind *codewindv1alpha
 		reqLogger.Info("Successfully removed TEKTON CRB", "namespace", codewind.Namespace, "name", deploymentOptions.CodewindTektonRoleBindingName, "finalizer", defaults.CodewindFinalizerName)
 	}
 
-	err = r.removeFinalizers(codewind)
+	err = r.removeFinalizer(codewind, defaults.CodewindFinalizerName)
 	if err != nil {
-		reqLogger.Error(err, "Failed to remove the Codewind finalizer", "namespace", codewind.Namespace, "name", codewind.Name)
+		reqLogger.Error(err, "Failed to remove the Codewind finalizer", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindFinalizerName)
 		return err
 	}
 	reqLogger.Info("Finalizer cleared", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindFinalizerName)
 
 	return nil
 }
+
+// handleCodewindKeycloakFinalizer : Remove the Keycloak client, access role and user grant of the deployment.
+// Failed attempts are retried a bounded number of times before the finalizer is released anyway, and the cleanup
+// is skipped entirely when the CR carries the force-delete annotation
+func (r *ReconcileCodewind) handleCodewindKeycloakFinalizer(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, codewindConfigMap OperatorConfigMapCodewind, reqLogger logr.Logger, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger.Info("Processing Finalizer", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindKeycloakFinalizerName)
+
+	annotations := codewind.GetAnnotations()
+	if force, _ := strconv.ParseBool(annotations[defaults.CodewindForceDeleteAnnotation]); force {
+		reqLogger.Info("Force delete requested, skipping Keycloak cleanup", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindKeycloakFinalizerName)
+		r.recorder.Event(codewind, corev1.EventTypeNormal, eventReasonCleanupSkipped, "Force delete requested, the Keycloak client was not removed")
+	} else if codewind.Status.KeycloakStatus == "" {
+		reqLogger.Info("Codewind was never registered with Keycloak, skipping Keycloak cleanup", "namespace", codewind.Namespace, "name", codewind.Name)
+	} else if err := r.removeCodewindFromKeycloak(codewind, deploymentOptions, codewindConfigMap, reqLogger, request); err != nil {
+		attempts, _ := strconv.Atoi(annotations[defaults.CodewindCleanupAttemptsAnnotation])
+		attempts++
+		if attempts < defaults.KeycloakCleanupMaxAttempts {
+			reqLogger.Error(err, "Keycloak cleanup failed, retrying", "namespace", codewind.Namespace, "name", codewind.Name, "attempt", attempts, "maxAttempts", defaults.KeycloakCleanupMaxAttempts)
+			r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonCleanupFailed, "Keycloak cleanup attempt %d of %d failed: %v", attempts, defaults.KeycloakCleanupMaxAttempts, err)
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[defaults.CodewindCleanupAttemptsAnnotation] = strconv.Itoa(attempts)
+			codewind.SetAnnotations(annotations)
+			if updateErr := r.client.Update(context.TODO(), codewind); updateErr != nil {
+				reqLogger.Error(updateErr, "Failed to record the Keycloak cleanup attempt", "namespace", codewind.Namespace, "name", codewind.Name)
+				return reconcile.Result{}, updateErr
+			}
+			return reconcile.Result{RequeueAfter: defaults.KeycloakCleanupRetryInterval}, nil
+		}
+		reqLogger.Error(err, "Keycloak cleanup failed, giving up. The Keycloak client and role must be removed manually", "namespace", codewind.Namespace, "name", codewind.Name, "client", "codewind-"+deploymentOptions.WorkspaceID)
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonCleanupAbandoned, "Keycloak cleanup failed, client %s and its role must be removed manually: %v", "codewind-"+deploymentOptions.WorkspaceID, err)
+	} else {
+		r.recorder.Eventf(codewind, corev1.EventTypeNormal, eventReasonCleanupCompleted, "Removed client %s from Keycloak", "codewind-"+deploymentOptions.WorkspaceID)
+	}
+
+	err := r.removeFinalizer(codewind, defaults.CodewindKeycloakFinalizerName)
+	if err != nil {
+		reqLogger.Error(err, "Failed to remove the Codewind finalizer", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindKeycloakFinalizerName)
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("Finalizer cleared", "namespace", codewind.Namespace, "name", codewind.Name, "finalizer", defaults.CodewindKeycloakFinalizerName)
+	return reconcile.Result{}, nil
+}
+
+// removeCodewindFromKeycloak : Connect to the Keycloak used by the deployment and remove its registration
+func (r *ReconcileCodewind) removeCodewindFromKeycloak(codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, codewindConfigMap OperatorConfigMapCodewind, reqLogger logr.Logger, request reconcile.Request) error {
+	keycloak, _, err := r.getKeycloakConnection(reqLogger, request, codewind, codewindConfigMap)
+	if err != nil {
+		return err
+	}
+	keycloakClientID := "codewind-" + deploymentOptions.WorkspaceID
+	reqLogger.Info("Removing Codewind from Keycloak", "namespace", codewind.Namespace, "name", codewind.Name, "client", keycloakClientID)
+	return security.RemoveCodewindFromKeycloak(keycloak.HTTPClient, deploymentOptions.WorkspaceID, keycloak.AuthURL, keycloak.Realm, keycloak.AdminUser, keycloak.AdminPass, codewind.Spec.Username, keycloakClientID)
+}
@@ -0,0 +1,96 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package codewind
+
+import (
+	"strconv"
+	"time"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resolveIdleTimeout : returns the idle timeout from the CR, else the operator config map. Zero disables idle culling
+func (r *ReconcileCodewind) resolveIdleTimeout(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, configMapTimeout string) time.Duration {
+	if codewind.Spec.IdleTimeout != nil {
+		return codewind.Spec.IdleTimeout.Duration
+	}
+	if configMapTimeout == "" {
+		return 0
+	}
+	idleTimeout, err := time.ParseDuration(configMapTimeout)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid idleTimeout in the operator config map", "Value", configMapTimeout)
+		r.recorder.Eventf(codewind, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid idleTimeout %q in the operator config map", configMapTimeout)
+		return 0
+	}
+	return idleTimeout
+}
+
+// isSuspended : true when the spec suspends the instance or the operator suspended it after its idle timeout
+func isSuspended(codewind *codewindv1alpha1.Codewind) bool {
+	return codewind.Spec.Suspended || isIdleSuspended(codewind)
+}
+
+// isIdleSuspended : true when the operator suspended the instance after its idle timeout
+func isIdleSuspended(codewind *codewindv1alpha1.Codewind) bool {
+	return codewind.GetAnnotations()[defaults.CodewindIdleSuspendedAnnotation] != ""
+}
+
+// checkIdleTimeout : scrapes the gatekeeper request counter and records the time of the last activity in the status.
+// Returns true when the instance has been idle for longer than the idle timeout and should be suspended.
+// The instance is never suspended when the gatekeeper cannot be scraped, which is reported by the ActivityTracked
+// condition
+func (r *ReconcileCodewind) checkIdleTimeout(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, metricName string, idleTimeout time.Duration) bool {
+	r.activityMutex.Lock()
+	defer r.activityMutex.Unlock()
+
+	if idleTimeout <= 0 || isSuspended(codewind) {
+		// Restart the idle clock when the instance resumes
+		delete(r.activityCounts, codewind.UID)
+		codewind.Status.LastActivityTime = nil
+		removeCondition(codewind, codewindv1alpha1.CodewindConditionActivityTracked)
+		return false
+	}
+
+	now := metav1.Now()
+	if codewind.Status.LastActivityTime == nil {
+		codewind.Status.LastActivityTime = &now
+	}
+
+	metricsURL := "https://" + deploymentOptions.CodewindGatekeeperServiceName + "." + codewind.Namespace + ".svc:" + strconv.Itoa(defaults.GatekeeperContainerPort) + defaults.GatekeeperMetricsPath
+	count, err := util.ScrapeMetricTotal(metricsURL, metricName, defaults.GatekeeperMetricsPath)
+	if err != nil {
+		reqLogger.Info("Unable to read gatekeeper activity, skipping idle check", "URL", metricsURL, "Error", err.Error())
+		condition := getCondition(codewind, codewindv1alpha1.CodewindConditionActivityTracked)
+		if condition == nil || condition.Status != corev1.ConditionFalse {
+			r.recorder.Eventf(codewind, corev1.EventTypeWarning, reasonActivityUnavailable, "Unable to read %s from %s, the instance is not suspended when idle: %v", metricName, metricsURL, err)
+		}
+		setCondition(codewind, codewindv1alpha1.CodewindConditionActivityTracked, corev1.ConditionFalse, reasonActivityUnavailable, "Unable to read "+metricName+" from "+metricsURL+": "+err.Error())
+		return false
+	}
+	setCondition(codewind, codewindv1alpha1.CodewindConditionActivityTracked, corev1.ConditionTrue, reasonActivityTracked, "Reading "+metricName+" from "+metricsURL)
+
+	// The first reading after the operator starts only sets the baseline. Activity while the operator was down
+	// cannot be seen, so the idle clock restarts rather than suspending an instance that may be in use
+	previous, seen := r.activityCounts[codewind.UID]
+	r.activityCounts[codewind.UID] = count
+	if !seen || count != previous {
+		codewind.Status.LastActivityTime = &now
+	}
+
+	return now.Sub(codewind.Status.LastActivityTime.Time) > idleTimeout
+}
@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package codewind
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// keycloakConnection : location and admin credentials of the Keycloak a Codewind instance registers with
+type keycloakConnection struct {
+	AuthHost   string
+	AuthURL    string
+	Realm      string
+	AdminUser  string
+	AdminPass  string
+	HTTPClient util.HTTPClient
+
+	// CredentialsRotatedAt : time the operator managed Keycloak last rotated its credentials
+	CredentialsRotatedAt *metav1.Time
+}
+
+// getKeycloakConnection : resolves the external server of spec.externalAuth, else the operator managed Keycloak
+// named by spec.keycloakDeployment or by the defaults of the tenant namespace. On failure also returns the condition
+// reason describing the problem
+func (r *ReconcileCodewind) getKeycloakConnection(reqLogger logr.Logger, request reconcile.Request, codewind *codewindv1alpha1.Codewind, codewindConfigMap OperatorConfigMapCodewind) (*keycloakConnection, string, error) {
+	if codewind.Spec.ExternalAuth != nil {
+		return r.getExternalKeycloakConnection(codewind, codewindConfigMap)
+	}
+
+	keycloakRef, err := r.keycloakReferenceFor(codewind)
+	if err != nil {
+		return nil, reasonKeycloakNotFound, errors.New("Unable to read the tenant defaults: " + err.Error())
+	}
+	keycloakPod, err := r.getKeycloakPod(reqLogger, request, keycloakRef)
+	if err != nil || keycloakPod == nil {
+		return nil, reasonKeycloakNotFound, errors.New("Unable to find a pod for Keycloak '" + keycloakRef.Name + "' in namespace '" + keycloakRef.Namespace + "'")
+	}
+	reqLogger.Info("Found the running Keycloak Pod", "Labels:", keycloakPod.GetLabels())
+
+	// Get the keycloak admin credentials
+	authID := keycloakPod.GetLabels()["authID"]
+	if authID == "" {
+		return nil, reasonKeycloakAuthIDMissing, errors.New("Keycloak pod " + keycloakPod.Name + " has no authID label")
+	}
+	keycloakAdminUser, keycloakAdminPass, err := r.getKeycloakAdminCredentials(authID, keycloakPod.Namespace)
+	if err != nil {
+		return nil, reasonKeycloakCredentials, errors.New("Unable to read the Keycloak admin credentials: " + err.Error())
+	}
+
+	// A rotation of the Keycloak credentials also rotates the client secrets registered with it
+	var credentialsRotatedAt *metav1.Time
+	keycloakCR := &codewindv1alpha1.Keycloak{}
+	err = r.client.Get(context.TODO(), keycloakRef, keycloakCR)
+	if err == nil {
+		credentialsRotatedAt = keycloakCR.Status.CredentialsRotatedAt
+	}
+
+	httpClient, err := util.NewManagedKeycloakHTTPClient(r.client, codewindConfigMap.HTTPClient, defaults.OperatorCASecretName, defaults.CABundleConfigMapName, types.NamespacedName{Name: "secret-keycloak-tls-" + authID, Namespace: keycloakPod.Namespace}, codewindConfigMap.Platform.OpenShift)
+	if err != nil {
+		return nil, reasonHTTPClientInvalid, errors.New("Unable to configure the Keycloak HTTP client: " + err.Error())
+	}
+
+	// The Keycloak ingress is named after the ingress domain of the namespace of the Keycloak
+	keycloakTenantDefaults, err := util.GetTenantDefaults(r.client, keycloakPod.Namespace)
+	if err != nil {
+		return nil, reasonKeycloakNotFound, errors.New("Unable to read the tenant defaults of the Keycloak namespace: " + err.Error())
+	}
+	keycloakIngressDomain := util.ValueOrDefault(keycloakTenantDefaults.IngressDomain, codewindConfigMap.IngressDomain)
+	keycloakAuthHostName := defaults.PrefixCodewindKeycloak + "-" + authID + "." + keycloakPod.Namespace + "." + keycloakIngressDomain
+	return &keycloakConnection{
+		AuthHost:             keycloakAuthHostName,
+		AuthURL:              "https://" + keycloakAuthHostName,
+		Realm:                codewindConfigMap.DefaultRealm,
+		AdminUser:            keycloakAdminUser,
+		AdminPass:            keycloakAdminPass,
+		HTTPClient:           httpClient,
+		CredentialsRotatedAt: credentialsRotatedAt,
+	}, "", nil
+}
+
+// getExternalKeycloakConnection : reads the credentials and CA bundle of spec.externalAuth from the namespace of the CR
+func (r *ReconcileCodewind) getExternalKeycloakConnection(codewind *codewindv1alpha1.Codewind, codewindConfigMap OperatorConfigMapCodewind) (*keycloakConnection, string, error) {
+	externalAuth := codewind.Spec.ExternalAuth
+	authURL, err := url.Parse(externalAuth.URL)
+	if err != nil || authURL.Host == "" {
+		return nil, reasonExternalAuthInvalid, errors.New("Invalid externalAuth URL '" + externalAuth.URL + "'")
+	}
+
+	secret := &corev1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: externalAuth.CredentialsSecret, Namespace: codewind.Namespace}, secret)
+	if err != nil {
+		return nil, reasonKeycloakCredentials, errors.New("Unable to read the Keycloak admin credentials: " + err.Error())
+	}
+	keycloakAdminUser := string(secret.Data["keycloak-admin-user"])
+	keycloakAdminPass := string(secret.Data["keycloak-admin-password"])
+	if keycloakAdminUser == "" || keycloakAdminPass == "" {
+		return nil, reasonKeycloakCredentials, errors.New("Secret " + externalAuth.CredentialsSecret + " must set keycloak-admin-user and keycloak-admin-password")
+	}
+
+	var caBundle []byte
+	if externalAuth.CABundleConfigMap != "" {
+		caBundleConfigMap := &corev1.ConfigMap{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: externalAuth.CABundleConfigMap, Namespace: codewind.Namespace}, caBundleConfigMap)
+		if err != nil {
+			return nil, reasonExternalAuthInvalid, errors.New("Unable to read the Keycloak CA bundle: " + err.Error())
+		}
+		caBundle = []byte(caBundleConfigMap.Data["ca.crt"])
+		if len(caBundle) == 0 {
+			return nil, reasonExternalAuthInvalid, errors.New("Config map " + externalAuth.CABundleConfigMap + " has no ca.crt key")
+		}
+	}
+	httpClient, err := util.NewKeycloakHTTPClient(r.client, codewindConfigMap.HTTPClient, caBundle)
+	if err != nil {
+		return nil, reasonHTTPClientInvalid, errors.New("Unable to configure the Keycloak HTTP client: " + err.Error())
+	}
+
+	return &keycloakConnection{
+		AuthHost:   authURL.Host,
+		AuthURL:    authURL.Scheme + "://" + authURL.Host,
+		Realm:      util.ValueOrDefault(externalAuth.Realm, codewindConfigMap.DefaultRealm),
+		AdminUser:  keycloakAdminUser,
+		AdminPass:  keycloakAdminPass,
+		HTTPClient: httpClient,
+	}, "", nil
+}
+
+// keycloakReferenceFor : returns the namespace and name of the Keycloak used by an instance, from its spec, else
+// from the defaults of its namespace
+func (r *ReconcileCodewind) keycloakReferenceFor(codewind *codewindv1alpha1.Codewind) (types.NamespacedName, error) {
+	tenantDefaults := util.TenantDefaults{}
+	if codewind.Spec.KeycloakDeployment == "" {
+		var err error
+		tenantDefaults, err = util.GetTenantDefaults(r.client, codewind.Namespace)
+		if err != nil {
+			return types.NamespacedName{}, err
+		}
+	}
+	return util.KeycloakReference(codewind, tenantDefaults), nil
+}
@@ -0,0 +1,130 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package codewind
+
+import (
+	"context"
+	"time"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	"github.com/eclipse/codewind-operator/pkg/security"
+	"github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// rotateCodewindCredentials : regenerates the Keycloak client secret of the instance and saves it in the gatekeeper
+// auth secret once spec.rotateCredentialsAt, or a later credential rotation of its Keycloak, is due. Returns how long
+// to wait for a rotation that is not due yet
+func (r *ReconcileCodewind) rotateCodewindCredentials(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, deploymentOptions DeploymentOptionsCodewind, keycloak *keycloakConnection, keycloakClientID string) (time.Duration, error) {
+	requested := codewind.Spec.RotateCredentialsAt
+	// Keycloak rotations that happened before this instance was created do not apply to it
+	keycloakRotation := keycloak.CredentialsRotatedAt
+	if keycloakRotation != nil && codewind.CreationTimestamp.Before(keycloakRotation) && (requested == nil || requested.Before(keycloakRotation)) {
+		requested = keycloakRotation
+	}
+	due, wait := util.CredentialRotationDue(requested, codewind.Status.CredentialsRotatedAt, time.Now())
+	if !due {
+		return wait, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.CodewindGatekeeperSecretAuthName, Namespace: codewind.Namespace}, secret)
+	if err != nil {
+		return 0, err
+	}
+
+	reqLogger.Info("Rotating the Codewind client secret", "Namespace", codewind.Namespace, "ClientID", keycloakClientID)
+	clientSecret, err := security.RotateCodewindClientSecret(keycloak.HTTPClient, keycloak.AuthURL, keycloak.Realm, keycloak.AdminUser, keycloak.AdminPass, keycloakClientID)
+	if err != nil {
+		return 0, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["client_secret"] = []byte(clientSecret)
+	err = r.client.Update(context.TODO(), secret)
+	if err != nil {
+		return 0, err
+	}
+
+	// Record the rotation straight away, the gatekeeper restarts when its pod annotation picks up the new time
+	rotatedAt := metav1.Now()
+	codewind.Status.CredentialsRotatedAt = &rotatedAt
+	r.recorder.Eventf(codewind, corev1.EventTypeNormal, eventReasonCredentialsRotated, "Rotated the secret of client %s", keycloakClientID)
+	return 0, r.updateCodewindStatus(codewind)
+}
+
+// codewindsForKeycloak : maps a Keycloak to the Codewind instances registered with it, in any namespace, including
+// instances that use the Keycloak of their tenant defaults
+func codewindsForKeycloak(currentClient client.Client) handler.ToRequestsFunc {
+	return func(object handler.MapObject) []reconcile.Request {
+		codewinds := &codewindv1alpha1.CodewindList{}
+		err := currentClient.List(context.TODO(), codewinds)
+		if err != nil {
+			log.Error(err, "Unable to list the Codewind instances of Keycloak", "Namespace", object.Meta.GetNamespace(), "Name", object.Meta.GetName())
+			return nil
+		}
+		keycloak := types.NamespacedName{Name: object.Meta.GetName(), Namespace: object.Meta.GetNamespace()}
+		requests := []reconcile.Request{}
+		tenantDefaultsByNamespace := map[string]util.TenantDefaults{}
+		for i := range codewinds.Items {
+			codewind := &codewinds.Items[i]
+			if codewind.Spec.ExternalAuth != nil {
+				continue
+			}
+			tenantDefaults, seen := tenantDefaultsByNamespace[codewind.Namespace]
+			if !seen && codewind.Spec.KeycloakDeployment == "" {
+				tenantDefaults, err = util.GetTenantDefaults(currentClient, codewind.Namespace)
+				if err != nil {
+					log.Error(err, "Unable to read the tenant defaults", "Namespace", codewind.Namespace)
+				}
+				tenantDefaultsByNamespace[codewind.Namespace] = tenantDefaults
+			}
+			if util.KeycloakReference(codewind, tenantDefaults) == keycloak {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: codewind.Name, Namespace: codewind.Namespace}})
+			}
+		}
+		return requests
+	}
+}
+
+// keycloakCredentialsRotated : passes Keycloak updates that record a new credential rotation
+var keycloakCredentialsRotated = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldKeycloak, ok := e.ObjectOld.(*codewindv1alpha1.Keycloak)
+		if !ok {
+			return false
+		}
+		newKeycloak, ok := e.ObjectNew.(*codewindv1alpha1.Keycloak)
+		if !ok || newKeycloak.Status.CredentialsRotatedAt == nil {
+			return false
+		}
+		return oldKeycloak.Status.CredentialsRotatedAt == nil || !oldKeycloak.Status.CredentialsRotatedAt.Equal(newKeycloak.Status.CredentialsRotatedAt)
+	},
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
@@ -0,0 +1,208 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package codewind
+
+import (
+	"context"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons reported on the Codewind status conditions
+const (
+	reasonKeycloakNotFound      = "KeycloakNotFound"
+	reasonKeycloakAuthIDMissing = "KeycloakAuthIDMissing"
+	reasonKeycloakCredentials   = "KeycloakCredentialsUnavailable"
+	reasonExternalAuthInvalid   = "ExternalAuthInvalid"
+	reasonHTTPClientInvalid     = "HTTPClientInvalid"
+	reasonRegistrationFailed    = "RegistrationFailed"
+	reasonRegistered            = "Registered"
+	reasonPVCPending            = "PVCPending"
+	reasonPVCBound              = "PVCBound"
+	reasonPVCLost               = "PVCLost"
+	reasonDeploymentAvailable   = "DeploymentAvailable"
+	reasonDeploymentUnavailable = "DeploymentUnavailable"
+	reasonAllComponentsReady    = "AllComponentsReady"
+	reasonComponentsNotReady    = "ComponentsNotReady"
+	reasonSuspended             = "Suspended"
+	reasonIdleSuspended         = "IdleSuspended"
+	reasonActivityTracked       = "ActivityTracked"
+	reasonActivityUnavailable   = "ActivityUnavailable"
+)
+
+// failureReasons : condition reasons that put the Codewind instance in the Failed phase
+var failureReasons = map[string]bool{
+	reasonKeycloakAuthIDMissing: true,
+	reasonKeycloakCredentials:   true,
+	reasonRegistrationFailed:    true,
+	reasonPVCLost:               true,
+}
+
+// getCondition : returns the condition of the requested type or nil when it has not been set
+func getCondition(codewind *codewindv1alpha1.Codewind, conditionType codewindv1alpha1.CodewindConditionType) *codewindv1alpha1.CodewindCondition {
+	for i := range codewind.Status.Conditions {
+		if codewind.Status.Conditions[i].Type == conditionType {
+			return &codewind.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// isConditionTrue : true when the condition of the requested type has been set to True
+func isConditionTrue(codewind *codewindv1alpha1.Codewind, conditionType codewindv1alpha1.CodewindConditionType) bool {
+	condition := getCondition(codewind, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// setCondition : adds or updates a condition, the transition time only changes when the status changes
+func setCondition(codewind *codewindv1alpha1.Codewind, conditionType codewindv1alpha1.CodewindConditionType, status corev1.ConditionStatus, reason string, message string) {
+	condition := getCondition(codewind, conditionType)
+	if condition == nil {
+		codewind.Status.Conditions = append(codewind.Status.Conditions, codewindv1alpha1.CodewindCondition{Type: conditionType})
+		condition = &codewind.Status.Conditions[len(codewind.Status.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Status = status
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = codewind.Generation
+}
+
+// removeCondition : removes the condition of the requested type
+func removeCondition(codewind *codewindv1alpha1.Codewind, conditionType codewindv1alpha1.CodewindConditionType) {
+	conditions := []codewindv1alpha1.CodewindCondition{}
+	for _, condition := range codewind.Status.Conditions {
+		if condition.Type != conditionType {
+			conditions = append(conditions, condition)
+		}
+	}
+	codewind.Status.Conditions = conditions
+}
+
+// setDeploymentCondition : sets a readiness condition from the availability of a deployment
+func setDeploymentCondition(codewind *codewindv1alpha1.Codewind, conditionType codewindv1alpha1.CodewindConditionType, deployment *appsv1.Deployment) {
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+		setCondition(codewind, conditionType, corev1.ConditionFalse, reasonSuspended, "Deployment "+deployment.Name+" is scaled to zero while Codewind is suspended")
+	} else if deployment.Status.AvailableReplicas > 0 {
+		setCondition(codewind, conditionType, corev1.ConditionTrue, reasonDeploymentAvailable, "Deployment "+deployment.Name+" has available replicas")
+	} else {
+		setCondition(codewind, conditionType, corev1.ConditionFalse, reasonDeploymentUnavailable, "Waiting for deployment "+deployment.Name+" to have available replicas")
+	}
+}
+
+// setStorageCondition : sets the StorageBound condition from the phase of the PFE PVC
+func setStorageCondition(codewind *codewindv1alpha1.Codewind, pvc *corev1.PersistentVolumeClaim) {
+	switch pvc.Status.Phase {
+	case corev1.ClaimBound:
+		setCondition(codewind, codewindv1alpha1.CodewindConditionStorageBound, corev1.ConditionTrue, reasonPVCBound, "PVC "+pvc.Name+" is bound")
+	case corev1.ClaimLost:
+		setCondition(codewind, codewindv1alpha1.CodewindConditionStorageBound, corev1.ConditionFalse, reasonPVCLost, "PVC "+pvc.Name+" has lost its volume")
+	default:
+		setCondition(codewind, codewindv1alpha1.CodewindConditionStorageBound, corev1.ConditionFalse, reasonPVCPending, "Waiting for PVC "+pvc.Name+" to be bound")
+	}
+}
+
+// summariseStatus : sets the Ready condition and phase from the other conditions
+func summariseStatus(codewind *codewindv1alpha1.Codewind) {
+	notReady := ""
+	required := []codewindv1alpha1.CodewindConditionType{
+		codewindv1alpha1.CodewindConditionKeycloakRegistered,
+		codewindv1alpha1.CodewindConditionStorageBound,
+		codewindv1alpha1.CodewindConditionPFEReady,
+		codewindv1alpha1.CodewindConditionGatekeeperReady,
+	}
+	for _, conditionType := range required {
+		if !isConditionTrue(codewind, conditionType) {
+			if notReady != "" {
+				notReady += ", "
+			}
+			notReady += string(conditionType)
+		}
+	}
+	if isIdleSuspended(codewind) {
+		setCondition(codewind, codewindv1alpha1.CodewindConditionReady, corev1.ConditionFalse, reasonIdleSuspended, "Codewind was suspended after its idle timeout, remove the "+defaults.CodewindIdleSuspendedAnnotation+" annotation to resume it")
+	} else if codewind.Spec.Suspended {
+		setCondition(codewind, codewindv1alpha1.CodewindConditionReady, corev1.ConditionFalse, reasonSuspended, "Codewind is suspended")
+	} else if notReady == "" {
+		setCondition(codewind, codewindv1alpha1.CodewindConditionReady, corev1.ConditionTrue, reasonAllComponentsReady, "Codewind is ready")
+	} else {
+		setCondition(codewind, codewindv1alpha1.CodewindConditionReady, corev1.ConditionFalse, reasonComponentsNotReady, "Waiting for: "+notReady)
+	}
+
+	codewind.Status.ObservedGeneration = codewind.Generation
+	switch {
+	case isSuspended(codewind):
+		codewind.Status.Phase = codewindv1alpha1.CodewindPhaseSuspended
+	case isConditionTrue(codewind, codewindv1alpha1.CodewindConditionReady):
+		codewind.Status.Phase = codewindv1alpha1.CodewindPhaseRunning
+	case hasFailedCondition(codewind):
+		codewind.Status.Phase = codewindv1alpha1.CodewindPhaseFailed
+	case isConditionTrue(codewind, codewindv1alpha1.CodewindConditionKeycloakRegistered):
+		codewind.Status.Phase = codewindv1alpha1.CodewindPhaseStarting
+	default:
+		codewind.Status.Phase = codewindv1alpha1.CodewindPhasePending
+	}
+}
+
+// hasFailedCondition : true when any condition is False for a reason that needs attention
+func hasFailedCondition(codewind *codewindv1alpha1.Codewind) bool {
+	for _, condition := range codewind.Status.Conditions {
+		if condition.Status == corev1.ConditionFalse && failureReasons[condition.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// trackCodewindUpgrade : records the progress of the rollout of the requested version. The current version only
+// moves forward once every deployment has replaced its pods with available pods from the new ReplicaSet
+func trackCodewindUpgrade(reqLogger logr.Logger, codewind *codewindv1alpha1.Codewind, version string, deployments ...*appsv1.Deployment) {
+	if codewind.Status.CurrentVersion == version {
+		codewind.Status.UpgradeState = codewindv1alpha1.UpgradeStateComplete
+		return
+	}
+	for _, deployment := range deployments {
+		if util.DeploymentRolloutFailed(deployment) {
+			if codewind.Status.UpgradeState != codewindv1alpha1.UpgradeStateFailed {
+				reqLogger.Info("Codewind upgrade failed, deployment exceeded its progress deadline", "Version", version, "Name", deployment.Name)
+			}
+			codewind.Status.UpgradeState = codewindv1alpha1.UpgradeStateFailed
+			return
+		}
+	}
+	for _, deployment := range deployments {
+		if !util.DeploymentRolloutComplete(deployment) {
+			if codewind.Status.UpgradeState != codewindv1alpha1.UpgradeStateProgressing {
+				reqLogger.Info("Rolling out Codewind version", "From", codewind.Status.CurrentVersion, "To", version)
+			}
+			codewind.Status.UpgradeState = codewindv1alpha1.UpgradeStateProgressing
+			return
+		}
+	}
+	reqLogger.Info("Codewind version rollout complete", "Version", version)
+	codewind.Status.CurrentVersion = version
+	codewind.Status.UpgradeState = codewindv1alpha1.UpgradeStateComplete
+}
+
+// updateCodewindStatus : summarises the conditions and saves the status of the Codewind CR
+func (r *ReconcileCodewind) updateCodewindStatus(codewind *codewindv1alpha1.Codewind) error {
+	summariseStatus(codewind)
+	return r.client.Status().Update(context.TODO(), codewind)
+}
@@ -11,6 +11,8 @@
 
 package defaults
 
+import "time"
+
 const (
 	// PrefixCodewindPerformance : Codewind performance application
 	PrefixCodewindPerformance = "codewind-performance"
@@ -21,6 +23,9 @@ const (
 	// PrefixCodewindGatekeeper : Codewind-gatekeeper application
 	PrefixCodewindGatekeeper = "codewind-gatekeeper"
 
+	// PrefixCodewindAsleep : Codewind-asleep application serving the gatekeeper host while the instance is suspended
+	PrefixCodewindAsleep = "codewind-asleep"
+
 	// PrefixCodewindKeycloak : Codewind-keycloak application
 	PrefixCodewindKeycloak = "codewind-keycloak"
 )
@@ -65,6 +70,27 @@ const (
 	// KeycloakContainerPort is the port at which Keycloak is exposed
 	KeycloakContainerPort = 8080
 
+	// KeycloakJGroupsPort : port used by clustered Keycloak replicas to discover each other and share their caches
+	KeycloakJGroupsPort = 7600
+
+	// KeycloakCacheOwners : number of replicas holding a copy of each distributed Keycloak cache entry
+	KeycloakCacheOwners = 2
+
+	// KeycloakDatabaseName : default name of an external Keycloak database
+	KeycloakDatabaseName = "keycloak"
+
+	// KeycloakDatabaseProbePeriod : seconds between the checks that the external Keycloak database accepts connections
+	KeycloakDatabaseProbePeriod = 10
+
+	// KeycloakDatabaseProbeTimeout : seconds before a check of the external Keycloak database fails
+	KeycloakDatabaseProbeTimeout = 5
+
+	// KeycloakPostgresPort : default port of a postgres Keycloak database
+	KeycloakPostgresPort = 5432
+
+	// KeycloakMySQLPort : default port of a mysql or mariadb Keycloak database
+	KeycloakMySQLPort = 3306
+
 	// GatekeeperContainerPort is the port at which the Gatekeeper is exposed
 	GatekeeperContainerPort = 9096
 
@@ -100,6 +126,21 @@ const (
 	// ROKSStorageClassGID references the storage class to use on ROKS
 	ROKSStorageClassGID = "ibmc-file-bronze-gid"
 
+	// EKSStorageClassRWX : storage class of the Amazon EFS CSI driver, which provides ReadWriteMany volumes on EKS
+	EKSStorageClassRWX = "efs-sc"
+
+	// GKEStorageClassRWX : storage class of the Filestore CSI driver, which provides ReadWriteMany volumes on GKE
+	GKEStorageClassRWX = "standard-rwx"
+
+	// AKSStorageClassRWX : storage class of Azure Files, which provides ReadWriteMany volumes on AKS
+	AKSStorageClassRWX = "azurefile"
+
+	// IngressClassNginx : ingress class of the NGINX ingress controller
+	IngressClassNginx = "nginx"
+
+	// KeycloakFSGroup : group of the Keycloak user, given ownership of block storage volumes
+	KeycloakFSGroup = 1000
+
 	// ConfigMapLocation : Codewind Operator config map defaults
 	ConfigMapLocation = "deploy/codewind-configmap.yaml"
 
@@ -108,4 +149,141 @@ const (
 
 	// CodewindFinalizerName : Codewind Cluster role binding finalizer
 	CodewindFinalizerName = "crb.finalizer.codewind.eclipse"
+
+	// CodewindKeycloakFinalizerName : Codewind Keycloak client, role and user grant finalizer
+	CodewindKeycloakFinalizerName = "keycloak.finalizer.codewind.eclipse"
+
+	// CodewindForceDeleteAnnotation : annotation that skips the Keycloak cleanup when a Codewind CR is deleted
+	CodewindForceDeleteAnnotation = "codewind.eclipse.org/force-delete"
+
+	// CodewindCleanupAttemptsAnnotation : annotation counting the failed Keycloak cleanup attempts of a deleted Codewind CR
+	CodewindCleanupAttemptsAnnotation = "codewind.eclipse.org/keycloak-cleanup-attempts"
+
+	// KeycloakCleanupMaxAttempts : number of failed Keycloak cleanup attempts before the finalizer is released anyway
+	KeycloakCleanupMaxAttempts = 5
+
+	// KeycloakCleanupRetryInterval : delay between Keycloak cleanup attempts
+	KeycloakCleanupRetryInterval = 30 * time.Second
+
+	// CodewindStateAnnotation : annotation on the gatekeeper route or ingress reporting whether the instance is running or asleep
+	CodewindStateAnnotation = "codewind.eclipse.org/state"
+
+	// CodewindStateRunning : value of the state annotation while the instance is running
+	CodewindStateRunning = "running"
+
+	// CodewindStateAsleep : value of the state annotation while the instance is suspended
+	CodewindStateAsleep = "asleep"
+
+	// CodewindAsleepImage : image of the pod that serves the gatekeeper host while the instance is suspended
+	CodewindAsleepImage = "busybox:1.31"
+
+	// AsleepContainerPort : port at which the asleep page is served
+	AsleepContainerPort = 8080
+
+	// GatekeeperMetricsPath : path of the gatekeeper Prometheus endpoint scraped by the idle detector
+	GatekeeperMetricsPath = "/metrics"
+
+	// CodewindIdleSuspendedAnnotation : annotation holding the time the operator suspended an idle instance, removing
+	// it resumes the instance
+	CodewindIdleSuspendedAnnotation = "codewind.eclipse.org/idle-suspended-at"
+
+	// GatekeeperActivityMetric : default gatekeeper request counter used to detect activity
+	GatekeeperActivityMetric = "http_requests_total"
+
+	// IdleCheckInterval : how often instances with an idle timeout are checked for activity
+	IdleCheckInterval = 5 * time.Minute
+
+	// KeycloakBackupLabel : label on the backup secrets naming the KeycloakBackup that created them
+	KeycloakBackupLabel = "codewind.eclipse.org/keycloak-backup"
+
+	// KeycloakBackupDataKey : key of the exported realm in a backup secret
+	KeycloakBackupDataKey = "realm.json"
+
+	// KeycloakBackupRetain : default number of backups kept for each KeycloakBackup
+	KeycloakBackupRetain = 7
+
+	// KeycloakBackupRetryInterval : delay before a failed backup is attempted again
+	KeycloakBackupRetryInterval = 5 * time.Minute
+
+	// KeycloakRestoreRetryInterval : delay between restore attempts
+	KeycloakRestoreRetryInterval = 30 * time.Second
+
+	// KeycloakRestoreMaxAttempts : number of failed restore attempts before the restore is marked as failed
+	KeycloakRestoreMaxAttempts = 10
+
+	// KeycloakAdminPasswordPendingKey : key of the Keycloak admin secret holding a new password while it is rotated
+	KeycloakAdminPasswordPendingKey = "keycloak-admin-password-pending"
+
+	// KeycloakAdminPasswordLength : length of generated Keycloak admin passwords
+	KeycloakAdminPasswordLength = 24
+
+	// CodewindCredentialsRotatedAnnotation : gatekeeper pod annotation holding the time of the latest client secret
+	// rotation, changing it restarts the gatekeeper with the new secret
+	CodewindCredentialsRotatedAnnotation = "codewind.eclipse.org/credentials-rotated-at"
+
+	// CredentialRotationRetryInterval : delay before a failed credential rotation is attempted again
+	CredentialRotationRetryInterval = 30 * time.Second
+
+	// CertificateRenewalWindow : default time before expiry at which generated TLS certificates are re-issued
+	CertificateRenewalWindow = 30 * 24 * time.Hour
+
+	// CodewindCertificateNotAfterAnnotation : gatekeeper pod annotation holding the expiry of its TLS certificate,
+	// changing it restarts the gatekeeper with a renewed certificate
+	CodewindCertificateNotAfterAnnotation = "codewind.eclipse.org/tls-not-after"
+
+	// OperatorCASecretName : secret in the operator namespace holding the CA that signs generated certificates
+	OperatorCASecretName = "codewind-operator-ca"
+
+	// CABundleConfigMapName : config map publishing the certificate of the operator CA in its ca.crt key, in the
+	// operator namespace and in the namespace of every Codewind instance
+	CABundleConfigMapName = "codewind-ca-bundle"
+
+	// CABundleMountPath : directory the CA bundle is mounted at in the PFE container
+	CABundleMountPath = "/etc/codewind/ca"
+
+	// CertificateIssueRetryInterval : how often a TLS secret requested from cert-manager is checked until it is issued
+	CertificateIssueRetryInterval = 30 * time.Second
+
+	// WebhookServiceName : service in the operator namespace that routes admission requests to the operator
+	WebhookServiceName = "codewind-operator-webhook"
+
+	// WebhookConfigurationName : name of the validating and mutating webhook configurations the operator injects its
+	// CA into
+	WebhookConfigurationName = "codewind-operator"
+
+	// WebhookPort : port the operator serves admission webhooks on
+	WebhookPort = 9443
+
+	// WebhookCertDir : directory the serving certificate of the admission webhooks is written to
+	WebhookCertDir = "/tmp/k8s-webhook-server/serving-certs"
+
+	// ValidateCodewindPath : path of the Codewind validating webhook
+	ValidateCodewindPath = "/validate-codewind"
+
+	// ValidateKeycloakPath : path of the Keycloak validating webhook
+	ValidateKeycloakPath = "/validate-keycloak"
+
+	// MutateCodewindPath : path of the Codewind defaulting webhook
+	MutateCodewindPath = "/mutate-codewind"
+
+	// MutateKeycloakPath : path of the Keycloak defaulting webhook
+	MutateKeycloakPath = "/mutate-keycloak"
+
+	// ConvertPath : path of the webhook converting Codewind resources between API versions
+	ConvertPath = "/convert"
+
+	// CodewindCRDName : name of the Codewind custom resource definition the operator injects its CA into
+	CodewindCRDName = "codewinds.codewind.eclipse.org"
+
+	// CodewindWorkspaceAnnotation : Codewind annotation holding the workspace ID that names the resources of the instance
+	CodewindWorkspaceAnnotation = "codewindWorkspace"
+
+	// CodewindLogLevel : log level of Codewind pods when neither the CR nor the operator config map set one
+	CodewindLogLevel = "info"
+
+	// CodewindStorageSize : size of the PFE PVC when neither the CR nor the operator config map set one
+	CodewindStorageSize = "10Gi"
+
+	// KeycloakStorageSize : size of the Keycloak PVC when neither the CR nor the operator config map set one
+	KeycloakStorageSize = "1Gi"
 )
@@ -12,6 +12,9 @@
 package keycloak
 
 import (
+	"strconv"
+	"time"
+
 	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
 	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
 	"github.com/eclipse/codewind-operator/pkg/util"
@@ -19,8 +22,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -62,9 +67,7 @@ func (r *ReconcileKeycloak) pvcForKeycloak(keycloak *codewindv1alpha1.Keycloak,
 			Labels:    ls,
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: []corev1.PersistentVolumeAccessMode{
-				"ReadWriteOnce",
-			},
+			AccessModes: util.PVCAccessModes(keycloak.Spec.Storage, corev1.ReadWriteOnce),
 			Resources: corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{
 					corev1.ResourceStorage: resource.MustParse(storageKeycloakSize),
@@ -73,7 +76,8 @@ func (r *ReconcileKeycloak) pvcForKeycloak(keycloak *codewindv1alpha1.Keycloak,
 		},
 	}
 
-	// If a storage class was passed in, set it in the PVC
+	// The storage class of the CR takes precedence over the one passed in
+	storageClassName = util.PVCStorageClassName(keycloak.Spec.Storage, storageClassName)
 	if storageClassName != "" {
 		pvc.Spec.StorageClassName = &storageClassName
 	}
@@ -107,9 +111,9 @@ func (r *ReconcileKeycloak) secretsForKeycloak(keycloak *codewindv1alpha1.Keyclo
 }
 
 // secretsTLSForKeycloak function takes in a Keycloak object and returns a TLS Secret for that object.
-func (r *ReconcileKeycloak) secretsTLSForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak) *corev1.Secret {
+func (r *ReconcileKeycloak) secretsTLSForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak, ca *util.CertificateAuthority) *corev1.Secret {
 	ls := labelsForKeycloak(keycloak)
-	pemPrivateKey, pemPublicCert, _ := util.GenerateCertificate(deploymentOptions.KeycloakIngressHost, deploymentOptions.KeycloakTLSCertTitle)
+	pemPrivateKey, pemPublicCert, _ := util.GenerateCertificate(deploymentOptions.KeycloakIngressHost, deploymentOptions.KeycloakTLSCertTitle, ca)
 
 	secret := &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
@@ -126,11 +130,28 @@ func (r *ReconcileKeycloak) secretsTLSForKeycloak(keycloak *codewindv1alpha1.Key
 			"tls.key": pemPrivateKey,
 		},
 	}
+	if ca != nil {
+		secret.StringData["ca.crt"] = ca.CertificatePEM
+	}
 	// Set Keycloak instance as the owner of the secret.
 	controllerutil.SetControllerReference(keycloak, secret, r.scheme)
 	return secret
 }
 
+// certificateForKeycloak function takes in a Keycloak object and returns a cert-manager Certificate that issues its
+// TLS Secret.
+func (r *ReconcileKeycloak) certificateForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak, issuer *codewindv1alpha1.IssuerReference, renewBefore time.Duration) *unstructured.Unstructured {
+	meta := metav1.ObjectMeta{
+		Name:      deploymentOptions.KeycloakCertificateName,
+		Namespace: keycloak.Namespace,
+		Labels:    labelsForKeycloak(keycloak),
+	}
+	certificate := util.BuildCertificate(meta, deploymentOptions.KeycloakTLSSecretsName, deploymentOptions.KeycloakIngressHost, issuer, renewBefore)
+	// Set Keycloak instance as the owner of the Certificate.
+	controllerutil.SetControllerReference(keycloak, certificate, r.scheme)
+	return certificate
+}
+
 // serviceForKeycloak function takes in a Keycloak object and returns a Service for that object.
 func (r *ReconcileKeycloak) serviceForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak) *corev1.Service {
 	ls := labelsForKeycloak(keycloak)
@@ -155,10 +176,103 @@ func (r *ReconcileKeycloak) serviceForKeycloak(keycloak *codewindv1alpha1.Keyclo
 	return service
 }
 
+// discoveryServiceForKeycloak function takes in a Keycloak object and returns the headless Service clustered replicas
+// use to find each other
+func (r *ReconcileKeycloak) discoveryServiceForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak) *corev1.Service {
+	ls := labelsForKeycloak(keycloak)
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentOptions.KeycloakDiscoveryServiceName,
+			Namespace: keycloak.Namespace,
+			Labels:    ls,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Selector:                 ls,
+			Ports: []corev1.ServicePort{
+				{
+					Port: int32(defaults.KeycloakJGroupsPort),
+					Name: defaults.PrefixCodewindKeycloak + "-jgroups",
+				},
+			},
+		},
+	}
+	// Set Keycloak instance as the owner of the service.
+	controllerutil.SetControllerReference(keycloak, service, r.scheme)
+	return service
+}
+
+// podDisruptionBudgetForKeycloak function takes in a Keycloak object and returns a PodDisruptionBudget that keeps
+// all but one replica running during voluntary disruptions such as node drains
+func (r *ReconcileKeycloak) podDisruptionBudgetForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak) *policyv1beta1.PodDisruptionBudget {
+	ls := labelsForKeycloak(keycloak)
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentOptions.KeycloakPDBName,
+			Namespace: keycloak.Namespace,
+			Labels:    ls,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: ls,
+			},
+		},
+	}
+	// Set Keycloak instance as the owner of the pod disruption budget.
+	controllerutil.SetControllerReference(keycloak, pdb, r.scheme)
+	return pdb
+}
+
+// clusterEnvForKeycloak returns the environment variables that let Keycloak replicas discover each other through the
+// discovery service and share their distributed caches
+func clusterEnvForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak) []corev1.EnvVar {
+	cacheOwners := strconv.Itoa(defaults.KeycloakCacheOwners)
+	return []corev1.EnvVar{
+		{
+			Name:  "JGROUPS_DISCOVERY_PROTOCOL",
+			Value: "dns.DNS_PING",
+		},
+		{
+			Name:  "JGROUPS_DISCOVERY_PROPERTIES",
+			Value: "dns_query=" + deploymentOptions.KeycloakDiscoveryServiceName + "." + keycloak.Namespace + ".svc.cluster.local",
+		},
+		{
+			Name:  "CACHE_OWNERS_COUNT",
+			Value: cacheOwners,
+		},
+		{
+			Name:  "CACHE_OWNERS_AUTH_SESSIONS_COUNT",
+			Value: cacheOwners,
+		},
+	}
+}
+
+// antiAffinityForKeycloak returns an affinity that prefers to schedule each Keycloak replica on a different node
+func antiAffinityForKeycloak(ls map[string]string) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: ls,
+						},
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+}
+
 // deploymentForKeycloak returns a Keycloak object
 func (r *ReconcileKeycloak) deploymentForKeycloak(keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak) *appsv1.Deployment {
 	ls := labelsForKeycloak(keycloak)
-	replicas := int32(1)
+	replicas := deploymentOptions.KeycloakReplicas
 
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -177,6 +291,7 @@ func (r *ReconcileKeycloak) deploymentForKeycloak(keycloak *codewindv1alpha1.Key
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: deploymentOptions.KeycloakServiceAccountName,
+					ImagePullSecrets:   deploymentOptions.ImagePullSecrets,
 					Volumes: []corev1.Volume{
 						{
 							Name: "keycloak-data",
@@ -189,8 +304,8 @@ func (r *ReconcileKeycloak) deploymentForKeycloak(keycloak *codewindv1alpha1.Key
 					},
 					Containers: []corev1.Container{{
 						Name:            defaults.PrefixCodewindKeycloak,
-						Image:           defaults.KeycloakImage + ":" + defaults.KeycloakImageTag,
-						ImagePullPolicy: corev1.PullAlways,
+						Image:           deploymentOptions.KeycloakImage,
+						ImagePullPolicy: deploymentOptions.ImagePullPolicy,
 						VolumeMounts: []corev1.VolumeMount{
 							{
 								Name:      "keycloak-data",
@@ -212,10 +327,6 @@ func (r *ReconcileKeycloak) deploymentForKeycloak(keycloak *codewindv1alpha1.Key
 								Name:  "PROXY_ADDRESS_FORWARDING",
 								Value: "true",
 							},
-							{
-								Name:  "DB_VENDOR",
-								Value: "h2",
-							},
 						},
 						Ports: []corev1.ContainerPort{
 							{ContainerPort: int32(defaults.KeycloakContainerPort)},
@@ -225,6 +336,30 @@ func (r *ReconcileKeycloak) deploymentForKeycloak(keycloak *codewindv1alpha1.Key
 			},
 		},
 	}
+	// Select the database, an external database replaces the H2 data volume
+	podSpec := &dep.Spec.Template.Spec
+	// The H2 database locks its files on the PVC, so the old pod is stopped before the new one starts
+	dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, databaseEnvForKeycloak(deploymentOptions.KeycloakDatabase)...)
+	if deploymentOptions.KeycloakDatabase != nil {
+		podSpec.Volumes = nil
+		podSpec.Containers[0].VolumeMounts = nil
+		podSpec.InitContainers = []corev1.Container{databaseInitContainerForKeycloak(deploymentOptions.KeycloakDatabase, deploymentOptions)}
+		podSpec.Containers[0].ReadinessProbe = databaseReadinessProbeForKeycloak()
+		dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+	} else if deploymentOptions.KeycloakFSGroup != nil {
+		// Block storage volumes are owned by root on some platforms, give the Keycloak user write access to the data
+		podSpec.SecurityContext = &corev1.PodSecurityContext{FSGroup: deploymentOptions.KeycloakFSGroup}
+	}
+	util.ApplyPodTemplate(&dep.Spec.Template.Spec, deploymentOptions.KeycloakPodTemplate)
+	// Cluster the replicas, spreading them across nodes unless the pod template sets its own affinity
+	if replicas > 1 {
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, clusterEnvForKeycloak(keycloak, deploymentOptions)...)
+		podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports, corev1.ContainerPort{Name: "jgroups", ContainerPort: int32(defaults.KeycloakJGroupsPort)})
+		if podSpec.Affinity == nil {
+			podSpec.Affinity = antiAffinityForKeycloak(ls)
+		}
+	}
 	// Set Keycloak instance as the owner of the deployment.
 	controllerutil.SetControllerReference(keycloak, dep, r.scheme)
 	return dep
@@ -280,7 +415,9 @@ func (r *ReconcileKeycloak) ingressForKeycloak(keycloak *codewindv1alpha1.Keyclo
 		"nginx.ingress.kubernetes.io/rewrite-target":     "/",
 		"nginx.ingress.kubernetes.io/backend-protocol":   "HTTP",
 		"nginx.ingress.kubernetes.io/force-ssl-redirect": "true",
-		"kubernetes.io/ingress.class":                    "nginx",
+	}
+	if deploymentOptions.KeycloakIngressClass != "" {
+		annotations["kubernetes.io/ingress.class"] = deploymentOptions.KeycloakIngressClass
 	}
 	ingress := &extv1beta1.Ingress{
 		TypeMeta: metav1.TypeMeta{
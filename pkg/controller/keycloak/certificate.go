@@ -0,0 +1,90 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloak
+
+import (
+	"context"
+	"time"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+	"github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// trackKeycloakCertificateExpiry : reports the expiry of the Keycloak certificate in the status and metrics. Returns
+// how long to wait before the certificate enters the renewal window
+func trackKeycloakCertificateExpiry(reqLogger logr.Logger, keycloak *codewindv1alpha1.Keycloak, secret *corev1.Secret, renewalWindow time.Duration) time.Duration {
+	notAfter, err := util.CertificateNotAfter(secret)
+	if err != nil {
+		reqLogger.Error(err, "Unable to read the Keycloak TLS certificate", "Namespace", secret.Namespace, "Name", secret.Name)
+		return 0
+	}
+	keycloak.Status.CertificateNotAfter = &metav1.Time{Time: notAfter}
+	metrics.SetCertificateExpiry(keycloak.Namespace, "Keycloak", keycloak.Name, notAfter)
+	wait := time.Until(notAfter.Add(-renewalWindow))
+	if wait <= 0 {
+		// Renewal failed or is pending, check again soon
+		return time.Minute
+	}
+	return wait
+}
+
+// reconcileKeycloakCertificate : requests the Keycloak certificate from cert-manager when an issuer is set and
+// removes a Certificate requested earlier when it is not. Returns true when cert-manager issues the TLS secret
+func (r *ReconcileKeycloak) reconcileKeycloakCertificate(reqLogger logr.Logger, keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak, issuer *codewindv1alpha1.IssuerReference, renewalWindow time.Duration) (bool, error) {
+	certificate := util.NewCertificate()
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakCertificateName, Namespace: keycloak.Namespace}, certificate)
+	if err != nil && !k8serr.IsNotFound(err) {
+		if issuer == nil && meta.IsNoMatchError(err) {
+			// cert-manager is not installed, there is nothing to remove
+			return false, nil
+		}
+		return false, err
+	}
+	found := err == nil
+
+	if issuer == nil {
+		if found && metav1.IsControlledBy(certificate, keycloak) {
+			reqLogger.Info("Deleting the Keycloak Certificate, no issuer is set", "Namespace", certificate.GetNamespace(), "Name", certificate.GetName())
+			err = r.client.Delete(context.TODO(), certificate)
+			if err != nil && !k8serr.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	newCertificate := r.certificateForKeycloak(keycloak, deploymentOptions, issuer, renewalWindow)
+	if !found {
+		reqLogger.Info("Creating a new Keycloak Certificate", "Namespace", newCertificate.GetNamespace(), "Name", newCertificate.GetName(), "Issuer", issuer.Name)
+		err = r.client.Create(context.TODO(), newCertificate)
+		util.RecordCreate(r.recorder, r.scheme, keycloak, newCertificate, err)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			return false, err
+		}
+		return true, nil
+	}
+	if util.MergeCertificate(certificate, newCertificate) {
+		reqLogger.Info("Updating Keycloak Certificate to match the required spec.", "Namespace", certificate.GetNamespace(), "Name", certificate.GetName())
+		err = r.client.Update(context.TODO(), certificate)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,83 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloak
+
+import (
+	"context"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileKeycloakCluster : creates the discovery service and pod disruption budget of a clustered Keycloak, and
+// removes them again when Keycloak is scaled back to a single replica
+func (r *ReconcileKeycloak) reconcileKeycloakCluster(reqLogger logr.Logger, keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak) error {
+	clustered := deploymentOptions.KeycloakReplicas > 1
+
+	// Check if the Keycloak discovery Service already exists
+	discoveryService := &corev1.Service{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakDiscoveryServiceName, Namespace: keycloak.Namespace}, discoveryService)
+	if err != nil && k8serr.IsNotFound(err) {
+		if clustered {
+			newService := r.discoveryServiceForKeycloak(keycloak, deploymentOptions)
+			reqLogger.Info("Creating a new Keycloak discovery Service", "Namespace", newService.Namespace, "Name", newService.Name)
+			err = r.client.Create(context.TODO(), newService)
+			util.RecordCreate(r.recorder, r.scheme, keycloak, newService, err)
+			if err != nil && !k8serr.IsAlreadyExists(err) {
+				reqLogger.Error(err, "Failed to create new Keycloak discovery Service.", "Namespace", newService.Namespace, "Name", newService.Name)
+				return err
+			}
+		}
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get Keycloak discovery Service.")
+		return err
+	} else if !clustered {
+		reqLogger.Info("Removing the Keycloak discovery Service", "Namespace", discoveryService.Namespace, "Name", discoveryService.Name)
+		err = r.client.Delete(context.TODO(), discoveryService)
+		if err != nil && !k8serr.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to remove the Keycloak discovery Service.", "Namespace", discoveryService.Namespace, "Name", discoveryService.Name)
+			return err
+		}
+	}
+
+	// Check if the Keycloak PodDisruptionBudget already exists. A budget on a single replica would block node drains
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakPDBName, Namespace: keycloak.Namespace}, pdb)
+	if err != nil && k8serr.IsNotFound(err) {
+		if clustered {
+			newPDB := r.podDisruptionBudgetForKeycloak(keycloak, deploymentOptions)
+			reqLogger.Info("Creating a new Keycloak PodDisruptionBudget", "Namespace", newPDB.Namespace, "Name", newPDB.Name)
+			err = r.client.Create(context.TODO(), newPDB)
+			util.RecordCreate(r.recorder, r.scheme, keycloak, newPDB, err)
+			if err != nil && !k8serr.IsAlreadyExists(err) {
+				reqLogger.Error(err, "Failed to create new Keycloak PodDisruptionBudget.", "Namespace", newPDB.Namespace, "Name", newPDB.Name)
+				return err
+			}
+		}
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get Keycloak PodDisruptionBudget.")
+		return err
+	} else if !clustered {
+		reqLogger.Info("Removing the Keycloak PodDisruptionBudget", "Namespace", pdb.Namespace, "Name", pdb.Name)
+		err = r.client.Delete(context.TODO(), pdb)
+		if err != nil && !k8serr.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to remove the Keycloak PodDisruptionBudget.", "Namespace", pdb.Namespace, "Name", pdb.Name)
+			return err
+		}
+	}
+	return nil
+}
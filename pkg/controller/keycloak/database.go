@@ -0,0 +1,132 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloak
+
+import (
+	"errors"
+	"strconv"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// resolveKeycloakDatabase : returns the external database settings with their defaults applied, or nil when Keycloak
+// keeps its embedded H2 database
+func resolveKeycloakDatabase(database *codewindv1alpha1.KeycloakDatabase) (*codewindv1alpha1.KeycloakDatabase, error) {
+	if database == nil || database.Vendor == "" || database.Vendor == codewindv1alpha1.KeycloakDatabaseH2 {
+		return nil, nil
+	}
+	if database.Host == "" {
+		return nil, errors.New("database.host is required for the " + string(database.Vendor) + " database")
+	}
+	if database.CredentialsSecret == "" {
+		return nil, errors.New("database.credentialsSecret is required for the " + string(database.Vendor) + " database")
+	}
+	resolved := database.DeepCopy()
+	if resolved.Port == 0 {
+		switch resolved.Vendor {
+		case codewindv1alpha1.KeycloakDatabasePostgres:
+			resolved.Port = defaults.KeycloakPostgresPort
+		default:
+			resolved.Port = defaults.KeycloakMySQLPort
+		}
+	}
+	if resolved.Database == "" {
+		resolved.Database = defaults.KeycloakDatabaseName
+	}
+	return resolved, nil
+}
+
+// databaseEnvForKeycloak : returns the Keycloak environment variables selecting the database
+func databaseEnvForKeycloak(database *codewindv1alpha1.KeycloakDatabase) []corev1.EnvVar {
+	if database == nil {
+		return []corev1.EnvVar{
+			{
+				Name:  "DB_VENDOR",
+				Value: string(codewindv1alpha1.KeycloakDatabaseH2),
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		{
+			Name:  "DB_VENDOR",
+			Value: string(database.Vendor),
+		},
+		{
+			Name:  "DB_ADDR",
+			Value: database.Host,
+		},
+		{
+			Name:  "DB_PORT",
+			Value: strconv.Itoa(int(database.Port)),
+		},
+		{
+			Name:  "DB_DATABASE",
+			Value: database.Database,
+		},
+		{
+			Name: "DB_USER",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: database.CredentialsSecret}, Key: "username"}},
+		},
+		{
+			Name: "DB_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: database.CredentialsSecret}, Key: "password"}},
+		},
+	}
+}
+
+// databaseInitContainerForKeycloak : returns an init container that holds the Keycloak pod back until the external
+// database accepts connections, so the pod only becomes ready once its database is reachable
+func databaseInitContainerForKeycloak(database *codewindv1alpha1.KeycloakDatabase, deploymentOptions DeploymentOptionsKeycloak) corev1.Container {
+	return corev1.Container{
+		Name:            "wait-for-database",
+		Image:           deploymentOptions.KeycloakImage,
+		ImagePullPolicy: deploymentOptions.ImagePullPolicy,
+		Command: []string{
+			"/bin/bash",
+			"-c",
+			"until (echo > /dev/tcp/$DB_ADDR/$DB_PORT) >/dev/null 2>&1; do echo \"Waiting for database $DB_ADDR:$DB_PORT\"; sleep 2; done",
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "DB_ADDR",
+				Value: database.Host,
+			},
+			{
+				Name:  "DB_PORT",
+				Value: strconv.Itoa(int(database.Port)),
+			},
+		},
+	}
+}
+
+// databaseReadinessProbeForKeycloak : returns a probe that takes the Keycloak pod out of its service while the external
+// database does not accept connections. The init container only checks the database when the pod starts
+func databaseReadinessProbeForKeycloak() *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"/bin/bash",
+					"-c",
+					"(echo > /dev/tcp/$DB_ADDR/$DB_PORT) >/dev/null 2>&1",
+				},
+			},
+		},
+		PeriodSeconds:    defaults.KeycloakDatabaseProbePeriod,
+		TimeoutSeconds:   defaults.KeycloakDatabaseProbeTimeout,
+		FailureThreshold: 3,
+	}
+}
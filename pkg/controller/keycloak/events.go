@@ -0,0 +1,26 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloak
+
+// Reasons of the events recorded on Keycloak instances, in addition to those shared with the Codewind controller in util
+const (
+	eventReasonConfigMapUnavailable = "ConfigMapUnavailable"
+	eventReasonConfigMapInvalid     = "ConfigMapInvalid"
+	eventReasonDatabaseInvalid      = "DatabaseInvalid"
+	eventReasonReplicasIgnored      = "ReplicasIgnored"
+	eventReasonPodNotFound          = "KeycloakPodNotFound"
+	eventReasonRealmConfigured      = "RealmConfigured"
+	eventReasonRealmFailed          = "RealmConfigurationFailed"
+	eventReasonCredentialsRotated   = "CredentialsRotated"
+	eventReasonRotationFailed       = "CredentialsRotationFailed"
+	eventReasonCertificateRenewed   = "CertificateRenewed"
+)
@@ -13,25 +13,28 @@ package keycloak
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"strconv"
-	"strings"
+	"time"
 
 	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
 	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+	"github.com/eclipse/codewind-operator/pkg/platform"
 	"github.com/eclipse/codewind-operator/pkg/security"
 	"github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
-	storagev1 "k8s.io/api/storage/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -47,16 +50,28 @@ var log = logf.Log.WithName("controller_keycloak")
 
 // DeploymentOptionsKeycloak : Configuration settings of a Keycloak deployment
 type DeploymentOptionsKeycloak struct {
-	KeycloakServiceAccountName string
-	KeycloakPVCName            string
-	KeycloakSecretsName        string
-	KeycloakTLSSecretsName     string
-	KeycloakTLSCertTitle       string
-	KeycloakDeploymentName     string
-	KeycloakServiceName        string
-	KeycloakIngressName        string
-	KeycloakIngressHost        string
-	KeycloakAccessURL          string
+	KeycloakServiceAccountName   string
+	KeycloakPVCName              string
+	KeycloakSecretsName          string
+	KeycloakTLSSecretsName       string
+	KeycloakTLSCertTitle         string
+	KeycloakCertificateName      string
+	KeycloakDeploymentName       string
+	KeycloakServiceName          string
+	KeycloakDiscoveryServiceName string
+	KeycloakPDBName              string
+	KeycloakIngressName          string
+	KeycloakIngressHost          string
+	KeycloakAccessURL            string
+	KeycloakVersion              string
+	KeycloakImage                string
+	ImagePullPolicy              corev1.PullPolicy
+	ImagePullSecrets             []corev1.LocalObjectReference
+	KeycloakPodTemplate          *codewindv1alpha1.PodTemplateOverrides
+	KeycloakDatabase             *codewindv1alpha1.KeycloakDatabase
+	KeycloakReplicas             int32
+	KeycloakIngressClass         string
+	KeycloakFSGroup              *int64
 }
 
 // OperatorConfigMapCodewind : Configuration fields saved in the config map
@@ -65,6 +80,15 @@ type OperatorConfigMapCodewind struct {
 	StorageSize         string
 	KeycloakStorageSize string
 	DefaultRealm        string
+	ImageKeycloak       string
+	ImageTag            string
+	ImagePullPolicy     string
+	ImagePullSecrets    string
+	PodTemplateKeycloak string
+	CertRenewalWindow   string
+	CertIssuerName      string
+	CertIssuerKind      string
+	HTTPClient          util.HTTPClientSettings
 }
 
 // Add : creates a new Keycloak Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -75,10 +99,10 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler : returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	reconciler := &ReconcileKeycloak{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	reconciler := &ReconcileKeycloak{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("keycloak-controller")}
 	operatorNamespace := util.GetOperatorNamespace()
 	createOperatorConfigMap(reconciler, operatorNamespace)
-	return &ReconcileKeycloak{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return reconciler
 }
 
 func createOperatorConfigMap(reconciler *ReconcileKeycloak, operatorNamespace string) {
@@ -111,7 +135,7 @@ func createOperatorConfigMap(reconciler *ReconcileKeycloak, operatorNamespace st
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	// Create a new controller
-	c, err := controller.New("keycloak-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("keycloak-controller", mgr, controller.Options{Reconciler: metrics.InstrumentReconciler("keycloak-controller", r)})
 	if err != nil {
 		return err
 	}
@@ -131,6 +155,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch the Keycloak PVC to report the progress of its expansion
+	err = c.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &codewindv1alpha1.Keycloak{},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to the Keycloak deployment to catch pod changes that require keycloak database updates
 	src := &source.Kind{Type: &appsv1.Deployment{}}
 	h := &handler.EnqueueRequestForOwner{
@@ -156,6 +189,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch the tenant defaults so that a new ingress domain or storage class is applied to the Keycloaks of a namespace
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: util.RequestsForTenantDefaults(mgr.GetClient(), &codewindv1alpha1.KeycloakList{}),
+	}, util.TenantDefaultsChanged)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -164,8 +205,9 @@ var _ reconcile.Reconciler = &ReconcileKeycloak{}
 
 // ReconcileKeycloak reconciles a Keycloak object
 type ReconcileKeycloak struct {
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 
 // Reconcile : Reads that state of the cluster for a Keycloak object and makes changes between the current state and required Keycloak.Spec
@@ -176,26 +218,14 @@ type ReconcileKeycloak struct {
 func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling Keycloak")
-	isOpenshift, _, err := util.DetectOpenShift()
-	if err != nil {
-		reqLogger.Error(err, "An error occurred when detecting current infrastructure", "")
-	}
-
-	// Use ROKSStorageClassGID when it is available
-	storageClassName := ""
-	storageClassDef := &storagev1.StorageClass{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: defaults.ROKSStorageClassGID, Namespace: ""}, storageClassDef)
-	if err == nil {
-		reqLogger.Info("Using storageclass", "name", defaults.ROKSStorageClassGID)
-		storageClassName = defaults.ROKSStorageClassGID
-	}
 
 	// Fetch the Keycloak instance
 	keycloak := &codewindv1alpha1.Keycloak{}
-	err = r.client.Get(context.TODO(), request.NamespacedName, keycloak)
+	err := r.client.Get(context.TODO(), request.NamespacedName, keycloak)
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			// Keycloak resource not found. Ignoring since object must be deleted
+			metrics.DeleteCertificateExpiry(request.Namespace, "Keycloak", request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -203,22 +233,61 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
+	// When watching the whole cluster only namespaces matching the selector are managed
+	watched, err := util.NamespaceWatched(r.client, keycloak.Namespace)
+	if err != nil {
+		reqLogger.Error(err, "Unable to check the namespace against the watch namespace selector")
+		return reconcile.Result{}, err
+	}
+	if !watched {
+		reqLogger.Info("Ignoring Keycloak in a namespace that does not match the watch namespace selector")
+		return reconcile.Result{}, nil
+	}
+
 	// Fetch the config map
 	operatorNamespace := util.GetOperatorNamespace()
 	operatorConfigMap := &corev1.ConfigMap{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: defaults.OperatorConfigMapName, Namespace: operatorNamespace}, operatorConfigMap)
 	if err != nil {
 		reqLogger.Error(err, "Unable to read config map. Ensure one has been created in the same namespace as the operator", "name", defaults.OperatorConfigMapName)
+		r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonConfigMapUnavailable, "Unable to read the operator config map %s/%s: %v", operatorNamespace, defaults.OperatorConfigMapName, err)
 		return reconcile.Result{}, err
 	}
-	// Get fields we need from the configmap
 
+	// The platform profile supplies the storage, ingress and security defaults, the operator config map may name
+	// a different platform than the one detected at startup
+	profile, err := platform.Current(operatorConfigMap.Data)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring the platform of the operator config map", "Platform", profile.Name)
+		r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid platform in the operator config map: %v", err)
+	}
+
+	// Get fields we need from the configmap
 	configMapCodewind := OperatorConfigMapCodewind{
 		IngressDomain:       operatorConfigMap.Data["ingressDomain"],
 		StorageSize:         operatorConfigMap.Data["storageCodewindSize"],
-		KeycloakStorageSize: operatorConfigMap.Data["storageKeycloakSize"],
+		KeycloakStorageSize: util.ValueOrDefault(operatorConfigMap.Data["storageKeycloakSize"], defaults.KeycloakStorageSize),
 		DefaultRealm:        operatorConfigMap.Data["defaultRealm"],
+		ImageKeycloak:       operatorConfigMap.Data["imageKeycloak"],
+		ImageTag:            operatorConfigMap.Data["imageTag"],
+		ImagePullPolicy:     operatorConfigMap.Data["imagePullPolicy"],
+		ImagePullSecrets:    operatorConfigMap.Data["imagePullSecrets"],
+		PodTemplateKeycloak: operatorConfigMap.Data["podTemplateKeycloak"],
+		CertRenewalWindow:   operatorConfigMap.Data["certRenewalWindow"],
+		CertIssuerName:      operatorConfigMap.Data["certIssuerName"],
+		CertIssuerKind:      operatorConfigMap.Data["certIssuerKind"],
+		HTTPClient:          util.HTTPClientSettingsFromConfigMap(operatorConfigMap.Data),
+	}
+
+	// Apply the defaults of the tenant namespace over the operator config map
+	tenantDefaults, err := util.GetTenantDefaults(r.client, keycloak.Namespace)
+	if err != nil {
+		reqLogger.Error(err, "Unable to read the tenant defaults", "Namespace", keycloak.Namespace)
+		r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Unable to read the tenant defaults: %v", err)
+		return reconcile.Result{}, err
 	}
+	ingressDomain := util.ValueOrDefault(tenantDefaults.IngressDomain, configMapCodewind.IngressDomain)
+	storageClassName := util.ValueOrDefault(tenantDefaults.StorageClassName, profile.KeycloakStorageClass)
 
 	// Get the authID from the CR else generate and store a new authID
 	authID := r.getKeycloakAuthID(keycloak)
@@ -231,18 +300,76 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 	}
 
 	deploymentOptions := DeploymentOptionsKeycloak{
-		KeycloakServiceAccountName: defaults.PrefixCodewindKeycloak + "-" + authID,
-		KeycloakPVCName:            defaults.PrefixCodewindKeycloak + "-pvc-" + authID,
-		KeycloakSecretsName:        "secret-keycloak-user-" + authID,
-		KeycloakTLSSecretsName:     "secret-keycloak-tls-" + authID,
-		KeycloakTLSCertTitle:       "Keycloak" + "-" + authID,
-		KeycloakDeploymentName:     defaults.PrefixCodewindKeycloak + "-" + authID,
-		KeycloakServiceName:        defaults.PrefixCodewindKeycloak + "-" + authID,
-		KeycloakIngressName:        defaults.PrefixCodewindKeycloak + "-" + authID,
-		KeycloakIngressHost:        defaults.PrefixCodewindKeycloak + "-" + authID + "." + keycloak.Namespace + "." + configMapCodewind.IngressDomain,
-		KeycloakAccessURL:          "https://" + defaults.PrefixCodewindKeycloak + "-" + authID + "." + keycloak.Namespace + "." + configMapCodewind.IngressDomain,
+		KeycloakServiceAccountName:   defaults.PrefixCodewindKeycloak + "-" + authID,
+		KeycloakPVCName:              defaults.PrefixCodewindKeycloak + "-pvc-" + authID,
+		KeycloakSecretsName:          "secret-keycloak-user-" + authID,
+		KeycloakTLSSecretsName:       "secret-keycloak-tls-" + authID,
+		KeycloakTLSCertTitle:         "Keycloak" + "-" + authID,
+		KeycloakCertificateName:      "keycloak-tls-" + authID,
+		KeycloakDeploymentName:       defaults.PrefixCodewindKeycloak + "-" + authID,
+		KeycloakServiceName:          defaults.PrefixCodewindKeycloak + "-" + authID,
+		KeycloakDiscoveryServiceName: defaults.PrefixCodewindKeycloak + "-discovery-" + authID,
+		KeycloakPDBName:              defaults.PrefixCodewindKeycloak + "-" + authID,
+		KeycloakIngressName:          defaults.PrefixCodewindKeycloak + "-" + authID,
+		KeycloakIngressHost:          defaults.PrefixCodewindKeycloak + "-" + authID + "." + keycloak.Namespace + "." + ingressDomain,
+		KeycloakAccessURL:            "https://" + defaults.PrefixCodewindKeycloak + "-" + authID + "." + keycloak.Namespace + "." + ingressDomain,
+		ImagePullPolicy:              util.ResolvePullPolicy(keycloak.Spec.ImagePullPolicy, configMapCodewind.ImagePullPolicy),
+		ImagePullSecrets:             util.ResolvePullSecrets(keycloak.Spec.ImagePullSecrets, configMapCodewind.ImagePullSecrets),
+		KeycloakIngressClass:         profile.IngressClass,
+		KeycloakFSGroup:              profile.KeycloakFSGroup,
+	}
+
+	// Resolve the container image from the CR override, then the requested version, then the operator config map,
+	// then the built in default
+	var keycloakImage *codewindv1alpha1.ImageSpec
+	if keycloak.Spec.Images != nil {
+		keycloakImage = keycloak.Spec.Images.Keycloak
+	}
+	deploymentOptions.KeycloakVersion = util.ValueOrDefault(keycloak.Spec.Version, util.ValueOrDefault(configMapCodewind.ImageTag, defaults.KeycloakImageTag))
+	deploymentOptions.KeycloakImage = util.ResolveImage(keycloakImage,
+		util.ValueOrDefault(configMapCodewind.ImageKeycloak, defaults.KeycloakImage),
+		deploymentOptions.KeycloakVersion)
+	keycloak.Status.Image = deploymentOptions.KeycloakImage
+
+	// Resolve the resource and scheduling settings of the pod from the CR, then the operator config map
+	var keycloakPodTemplate *codewindv1alpha1.PodTemplateOverrides
+	if keycloak.Spec.PodTemplates != nil {
+		keycloakPodTemplate = keycloak.Spec.PodTemplates.Keycloak
+	}
+	deploymentOptions.KeycloakPodTemplate, err = util.ResolvePodTemplate(keycloakPodTemplate, configMapCodewind.PodTemplateKeycloak)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid pod template defaults in the operator config map", "Key", "podTemplateKeycloak")
+		r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid podTemplateKeycloak in the operator config map: %v", err)
 	}
 
+	// Resolve the external database, nil keeps the embedded H2 database on the Keycloak PVC
+	deploymentOptions.KeycloakDatabase, err = resolveKeycloakDatabase(keycloak.Spec.Database)
+	if err != nil {
+		reqLogger.Error(err, "Invalid Keycloak database settings, waiting for the CR to be corrected")
+		r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonDatabaseInvalid, "Invalid database settings: %v", err)
+		keycloak.Status.Message = "Invalid database settings: " + err.Error()
+		if statusErr := r.client.Status().Update(context.TODO(), keycloak); statusErr != nil {
+			return reconcile.Result{}, statusErr
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Resolve the number of replicas, clustering needs a database shared by every replica
+	deploymentOptions.KeycloakReplicas = 1
+	replicasMessage := ""
+	if keycloak.Spec.Replicas != nil && *keycloak.Spec.Replicas > 1 {
+		if deploymentOptions.KeycloakDatabase == nil {
+			reqLogger.Error(errors.New("Keycloak replicas require an external database"), "Running a single Keycloak replica on the embedded H2 database", "Replicas", *keycloak.Spec.Replicas)
+			replicasMessage = fmt.Sprintf("Running 1 of %d replicas, more than one replica requires an external database", *keycloak.Spec.Replicas)
+			if keycloak.Status.Message != replicasMessage {
+				r.recorder.Event(keycloak, corev1.EventTypeWarning, eventReasonReplicasIgnored, replicasMessage)
+			}
+		} else {
+			deploymentOptions.KeycloakReplicas = *keycloak.Spec.Replicas
+		}
+	}
+	keycloak.Status.Message = replicasMessage
+
 	// Check if the Keycloak Service account already exist, if not create a new one
 	serviceAccount := &corev1.ServiceAccount{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakServiceAccountName, Namespace: keycloak.Namespace}, serviceAccount)
@@ -251,6 +378,7 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		newServiceAccount := r.serviceAccountForKeycloak(keycloak, deploymentOptions)
 		reqLogger.Info("Creating a new service account", "Namespace", newServiceAccount.Namespace, "Name", newServiceAccount.Name)
 		err = r.client.Create(context.TODO(), newServiceAccount)
+		util.RecordCreate(r.recorder, r.scheme, keycloak, newServiceAccount, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Secret.", "Namespace", newServiceAccount.Namespace, "Name", newServiceAccount.Name)
 			return reconcile.Result{}, err
@@ -268,6 +396,7 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		secretUser = r.secretsForKeycloak(keycloak, deploymentOptions)
 		reqLogger.Info("Creating a new Keycloak Secret", "Namespace", secretUser.Namespace, "Name", secretUser.Name)
 		err = r.client.Create(context.TODO(), secretUser)
+		util.RecordCreate(r.recorder, r.scheme, keycloak, secretUser, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Keycloak Secret.", "Namespace", secretUser.Namespace, "Name", secretUser.Name)
 			return reconcile.Result{}, err
@@ -278,13 +407,42 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 	}
 
 	// Check if the Keycloak TLS Secrets already exist, if not create new ones
+	renewalWindow, err := util.ResolveRenewalWindow(configMapCodewind.CertRenewalWindow, defaults.CertificateRenewalWindow)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid certRenewalWindow in the operator config map", "Value", configMapCodewind.CertRenewalWindow)
+		r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonConfigMapInvalid, "Ignoring invalid certRenewalWindow %q in the operator config map", configMapCodewind.CertRenewalWindow)
+	}
+	// Sign the generated certificate with the operator CA
+	ca, err := util.EnsureCertificateAuthority(r.client, defaults.OperatorCASecretName, defaults.CABundleConfigMapName)
+	if err != nil {
+		reqLogger.Error(err, "Unable to load the operator CA, using a self-signed certificate")
+		ca = nil
+	}
+
+	// When a cert-manager issuer is set the TLS secret is issued by cert-manager instead
+	issuer := util.ResolveIssuer(keycloak.Spec.IssuerRef, configMapCodewind.CertIssuerName, configMapCodewind.CertIssuerKind)
+	certManaged, err := r.reconcileKeycloakCertificate(reqLogger, keycloak, deploymentOptions, issuer, renewalWindow)
+	if err != nil {
+		if issuer != nil {
+			reqLogger.Error(err, "Unable to request the Keycloak certificate from cert-manager, using a self-signed certificate", "Issuer", issuer.Name)
+		} else {
+			reqLogger.Error(err, "Unable to remove the Keycloak Certificate", "Namespace", keycloak.Namespace, "Name", deploymentOptions.KeycloakCertificateName)
+		}
+	}
+	certificateWait := time.Duration(0)
 	secretTLS := &corev1.Secret{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakTLSSecretsName, Namespace: keycloak.Namespace}, secretTLS)
-	if err != nil && k8serr.IsNotFound(err) {
+	if err != nil && k8serr.IsNotFound(err) && certManaged {
+		// The ingress serves the certificate once cert-manager has issued the secret
+		reqLogger.Info("Waiting for cert-manager to issue the Keycloak TLS secret", "Namespace", keycloak.Namespace, "Name", deploymentOptions.KeycloakTLSSecretsName)
+		secretTLS = nil
+		certificateWait = defaults.CertificateIssueRetryInterval
+	} else if err != nil && k8serr.IsNotFound(err) {
 		// Define a new Secrets object
-		secretTLS = r.secretsTLSForKeycloak(keycloak, deploymentOptions)
+		secretTLS = r.secretsTLSForKeycloak(keycloak, deploymentOptions, ca)
 		reqLogger.Info("Creating a new Keycloak TLS Secret", "Namespace", secretTLS.Namespace, "Name", secretTLS.Name)
 		err = r.client.Create(context.TODO(), secretTLS)
+		util.RecordCreate(r.recorder, r.scheme, keycloak, secretTLS, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Keycloak TLS Secret.", "Namespace", secretTLS.Namespace, "Name", secretTLS.Name)
 			return reconcile.Result{}, err
@@ -292,39 +450,76 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get Keycloak TLS Secret.")
 		return reconcile.Result{}, err
+	} else if !certManaged {
+		// Re-issue the certificate once it enters the renewal window or when it is not signed by the operator CA. The
+		// secret is only served by the ingress, which reloads it without a restart of Keycloak
+		renewed, _, err := util.RenewCertificateIfDue(secretTLS, deploymentOptions.KeycloakIngressHost, deploymentOptions.KeycloakTLSCertTitle, renewalWindow, ca)
+		if err != nil {
+			reqLogger.Error(err, "Failed to renew the Keycloak TLS certificate.", "Namespace", secretTLS.Namespace, "Name", secretTLS.Name)
+			return reconcile.Result{}, err
+		}
+		if renewed {
+			reqLogger.Info("Renewing the Keycloak TLS certificate", "Namespace", secretTLS.Namespace, "Name", secretTLS.Name)
+			err = r.client.Update(context.TODO(), secretTLS)
+			if err != nil {
+				reqLogger.Error(err, "Failed to update Keycloak TLS Secret.", "Namespace", secretTLS.Namespace, "Name", secretTLS.Name)
+				return reconcile.Result{}, err
+			}
+			r.recorder.Eventf(keycloak, corev1.EventTypeNormal, eventReasonCertificateRenewed, "Renewed the Keycloak TLS certificate in secret %s", secretTLS.Name)
+		}
+	}
+	if secretTLS != nil {
+		certificateWait = trackKeycloakCertificateExpiry(reqLogger, keycloak, secretTLS, renewalWindow)
 	}
 
-	// Check if the Keycloak PVC already exist, if not create a new one
+	// Check if the Keycloak PVC already exist, if not create a new one. An existing PVC is expanded when the
+	// requested size grows. An external database needs no PVC
+	storageSize := configMapCodewind.KeycloakStorageSize
+	if keycloak.Spec.StorageSize != "" {
+		storageSize = keycloak.Spec.StorageSize
+	}
 	keycloakPVC := &corev1.PersistentVolumeClaim{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakPVCName, Namespace: keycloak.Namespace}, keycloakPVC)
-	if err != nil && k8serr.IsNotFound(err) {
+	if err != nil && k8serr.IsNotFound(err) && deploymentOptions.KeycloakDatabase != nil {
+		reqLogger.Info("Using an external database, skipping the Keycloak PVC", "Vendor", deploymentOptions.KeycloakDatabase.Vendor, "Host", deploymentOptions.KeycloakDatabase.Host)
+		keycloak.Status.Storage = nil
+	} else if err != nil && k8serr.IsNotFound(err) {
 		// Define a new PVC object
-
-		storageSize := configMapCodewind.KeycloakStorageSize
-		if keycloak.Spec.StorageSize != "" {
-			storageSize = keycloak.Spec.StorageSize
-		}
-
 		newKeycloakPVC := r.pvcForKeycloak(keycloak, deploymentOptions, storageClassName, storageSize)
 		reqLogger.Info("Creating a new PVC", "Namespace", newKeycloakPVC.Namespace, "Name", newKeycloakPVC.Name)
 		err = r.client.Create(context.TODO(), newKeycloakPVC)
+		util.RecordCreate(r.recorder, r.scheme, keycloak, newKeycloakPVC, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new PVC.", "Namespace", newKeycloakPVC.Namespace, "Name", newKeycloakPVC.Name)
 			return reconcile.Result{}, err
 		}
+		keycloak.Status.Storage = util.NewPVCStorageStatus(newKeycloakPVC)
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get PVC.")
 		return reconcile.Result{}, err
+	} else {
+		storage, expanded, err := util.ExpandPVC(r.client, keycloakPVC, storageSize)
+		if storage != nil {
+			util.RecordStorage(r.recorder, keycloak, keycloak.Status.Storage, storage, expanded)
+			keycloak.Status.Storage = storage
+		}
+		if err != nil {
+			reqLogger.Error(err, "Failed to expand PVC.", "Namespace", keycloakPVC.Namespace, "Name", keycloakPVC.Name, "Size", storageSize)
+			return reconcile.Result{}, err
+		}
+		if expanded {
+			reqLogger.Info("Expanding PVC", "Namespace", keycloakPVC.Namespace, "Name", keycloakPVC.Name, "Size", storageSize)
+		}
 	}
 
 	// Check if the Keycloak Deployment already exists, if not create a new one
+	dep := r.deploymentForKeycloak(keycloak, deploymentOptions)
 	deployment := &appsv1.Deployment{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakDeploymentName, Namespace: keycloak.Namespace}, deployment)
 	if err != nil && k8serr.IsNotFound(err) {
-		// Define a new Deployment
-		dep := r.deploymentForKeycloak(keycloak, deploymentOptions)
 		reqLogger.Info("Creating a new Deployment.", "Namespace", dep.Namespace, "Name", dep.Name)
 		err = r.client.Create(context.TODO(), dep)
+		util.RecordCreate(r.recorder, r.scheme, keycloak, dep, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Deployment.", "Namespace", dep.Namespace, "Name", dep.Name)
 			return reconcile.Result{}, err
@@ -335,8 +530,18 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 	} else if err != nil {
 		reqLogger.Error(err, "Failed to get Deployment.")
 		return reconcile.Result{}, err
+	} else if util.MergeDeployment(deployment, dep) {
+		reqLogger.Info("Updating Deployment to match the required spec.", "Namespace", deployment.Namespace, "Name", deployment.Name)
+		err = r.client.Update(context.TODO(), deployment)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update Deployment.", "Namespace", deployment.Namespace, "Name", deployment.Name)
+			return reconcile.Result{}, err
+		}
 	}
 
+	// Track the rollout of the requested version
+	trackKeycloakUpgrade(reqLogger, keycloak, deploymentOptions.KeycloakVersion, deployment)
+
 	// Check if the Keycloak Service already exists, if not create a new one
 	service := &corev1.Service{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakServiceName, Namespace: keycloak.Namespace}, service)
@@ -345,6 +550,7 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		ser := r.serviceForKeycloak(keycloak, deploymentOptions)
 		reqLogger.Info("Creating a new Service", "Namespace", ser.Namespace, "Name", ser.Name)
 		err = r.client.Create(context.TODO(), ser)
+		util.RecordCreate(r.recorder, r.scheme, keycloak, ser, err)
 		if err != nil && !k8serr.IsAlreadyExists(err) {
 			reqLogger.Error(err, "Failed to create new Service.", "Namespace", ser.Namespace, "Name", ser.Name)
 			return reconcile.Result{}, err
@@ -354,7 +560,13 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		return reconcile.Result{}, err
 	}
 
-	if isOpenshift {
+	// Check the clustering resources match the number of replicas
+	err = r.reconcileKeycloakCluster(reqLogger, keycloak, deploymentOptions)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if profile.OpenShift {
 		// Check if the Keycloak Route already exists, if not create a new one
 		route := &routev1.Route{}
 		err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakIngressName, Namespace: keycloak.Namespace}, route)
@@ -363,6 +575,7 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 			openshiftRoute := r.routeForKeycloak(keycloak, deploymentOptions)
 			reqLogger.Info("Creating a new route", "Namespace", openshiftRoute.Namespace, "Name", openshiftRoute.Name)
 			err = r.client.Create(context.TODO(), openshiftRoute)
+			util.RecordCreate(r.recorder, r.scheme, keycloak, openshiftRoute, err)
 			if err != nil && !k8serr.IsAlreadyExists(err) {
 				reqLogger.Error(err, "Failed to create new route.", "Namespace", openshiftRoute.Namespace, "Name", openshiftRoute.Name)
 				return reconcile.Result{}, err
@@ -382,6 +595,7 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 			ing := r.ingressForKeycloak(keycloak, deploymentOptions)
 			reqLogger.Info("Creating a new Ingress", "Namespace", ing.Namespace, "Name", ing.Name)
 			err = r.client.Create(context.TODO(), ing)
+			util.RecordCreate(r.recorder, r.scheme, keycloak, ing, err)
 			if err != nil && !k8serr.IsAlreadyExists(err) {
 				reqLogger.Error(err, "Failed to create new Ingress.", "Namespace", ing.Namespace, "Name", ing.Name)
 				return reconcile.Result{}, err
@@ -394,9 +608,17 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 		}
 	}
 
-	// Update Keycloak default realm
+	tlsSecretName := types.NamespacedName{Name: deploymentOptions.KeycloakTLSSecretsName, Namespace: keycloak.Namespace}
+	httpClient, err := util.NewManagedKeycloakHTTPClient(r.client, configMapCodewind.HTTPClient, defaults.OperatorCASecretName, defaults.CABundleConfigMapName, tlsSecretName, profile.OpenShift)
+	if err != nil {
+		reqLogger.Error(err, "Unable to configure the Keycloak HTTP client")
+		return reconcile.Result{}, err
+	}
+
+	// Update Keycloak default realm and rotate the admin password when requested
+	result := reconcile.Result{}
 	reqLogger.Info("Checking Keycloak Pod", "instance", authID)
-	keycloakPod, err := fetchKeycloakPod(r.client, keycloak.Name)
+	keycloakPod, err := fetchKeycloakPod(r.client, keycloak.Namespace, keycloak.Name)
 	if err == nil && keycloakPod != nil {
 		reqLogger.Info("Keycloak Pod status", "phase", keycloakPod.Status.Phase)
 		if keycloakPod.Status.Phase == "Running" {
@@ -410,25 +632,41 @@ func (r *ReconcileKeycloak) Reconcile(request reconcile.Request) (reconcile.Resu
 					reqLogger.Error(err, "Unable to find the Keycloak secret when adding realm", "Namespace", keycloak.Namespace, "name", deploymentOptions.KeycloakSecretsName)
 					return reconcile.Result{}, err
 				}
-				err = security.AddCodewindRealmToKeycloak(deploymentOptions.KeycloakAccessURL, defaultRealm, string(secretUser.Data["keycloak-admin-user"]), string(secretUser.Data["keycloak-admin-password"]))
+				err = security.AddCodewindRealmToKeycloak(httpClient, deploymentOptions.KeycloakAccessURL, defaultRealm, string(secretUser.Data["keycloak-admin-user"]), string(secretUser.Data["keycloak-admin-password"]))
 				if err != nil {
 					reqLogger.Error(err, "Failed configuring keycloak with codewind default realm", "Namespace", keycloak.Namespace, "realm", defaultRealm)
+					r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonRealmFailed, "Failed to configure realm %s: %v", defaultRealm, err)
 					return reconcile.Result{}, err
 				}
+				r.recorder.Eventf(keycloak, corev1.EventTypeNormal, eventReasonRealmConfigured, "Configured realm %s", defaultRealm)
+			}
+			result.RequeueAfter, err = r.rotateKeycloakCredentials(reqLogger, keycloak, deploymentOptions, httpClient)
+			if err != nil {
+				reqLogger.Error(err, "Failed to rotate the Keycloak admin password", "Namespace", keycloak.Namespace, "Name", keycloak.Name)
+				r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonRotationFailed, "Failed to rotate the admin password: %v", err)
+				result.RequeueAfter = defaults.CredentialRotationRetryInterval
 			}
 		}
+	} else if err != nil {
+		reqLogger.Info("Keycloak Pod not found", "instance", authID)
+		r.recorder.Eventf(keycloak, corev1.EventTypeWarning, eventReasonPodNotFound, "No pod found for deployment %s", deploymentOptions.KeycloakDeploymentName)
+	}
+
+	if certificateWait > 0 && (result.RequeueAfter == 0 || certificateWait < result.RequeueAfter) {
+		result.RequeueAfter = certificateWait
 	}
 
 	err = r.client.Status().Update(context.TODO(), keycloak)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
-func fetchKeycloakPod(currentClient client.Client, authDeploymentName string) (*corev1.Pod, error) {
+func fetchKeycloakPod(currentClient client.Client, namespace string, authDeploymentName string) (*corev1.Pod, error) {
 	keycloaks := &corev1.PodList{}
 	opts := []client.ListOption{
+		client.InNamespace(namespace),
 		client.MatchingLabels{"app": defaults.PrefixCodewindKeycloak, "authName": authDeploymentName},
 	}
 	err := currentClient.List(context.TODO(), keycloaks, opts...)
@@ -436,8 +674,8 @@ func fetchKeycloakPod(currentClient client.Client, authDeploymentName string) (*
 		err = fmt.Errorf("Unable to find Keycloak authName:'%s'", authDeploymentName)
 		return nil, err
 	}
-	keycloakPod := keycloaks.Items[0]
-	return &keycloakPod, nil
+	// Any replica can serve the admin API, prefer one that is ready
+	return util.SelectPod(keycloaks.Items), nil
 }
 
 func (r *ReconcileKeycloak) getKeycloakAuthID(keycloak *codewindv1alpha1.Keycloak) string {
@@ -446,7 +684,7 @@ func (r *ReconcileKeycloak) getKeycloakAuthID(keycloak *codewindv1alpha1.Keycloa
 }
 
 func (r *ReconcileKeycloak) setKeycloakAuthID(keycloak *codewindv1alpha1.Keycloak) (string, error) {
-	newAuthID := strings.ToLower(strconv.FormatInt(util.CreateTimestamp(), 36) + util.GenerateRandomString(4))
+	newAuthID := util.GenerateInstanceID()
 	annotations := keycloak.GetAnnotations()
 	if annotations == nil {
 		annotations = make(map[string]string)
@@ -459,3 +697,26 @@ func (r *ReconcileKeycloak) setKeycloakAuthID(keycloak *codewindv1alpha1.Keycloa
 	}
 	return newAuthID, nil
 }
+
+// trackKeycloakUpgrade : records the progress of the rollout of the requested version. The current version only
+// moves forward once the deployment has replaced its pods with available pods from the new ReplicaSet
+func trackKeycloakUpgrade(reqLogger logr.Logger, keycloak *codewindv1alpha1.Keycloak, version string, deployment *appsv1.Deployment) {
+	switch {
+	case keycloak.Status.CurrentVersion == version:
+		keycloak.Status.UpgradeState = codewindv1alpha1.UpgradeStateComplete
+	case util.DeploymentRolloutFailed(deployment):
+		if keycloak.Status.UpgradeState != codewindv1alpha1.UpgradeStateFailed {
+			reqLogger.Info("Keycloak upgrade failed, deployment exceeded its progress deadline", "Version", version, "Name", deployment.Name)
+		}
+		keycloak.Status.UpgradeState = codewindv1alpha1.UpgradeStateFailed
+	case !util.DeploymentRolloutComplete(deployment):
+		if keycloak.Status.UpgradeState != codewindv1alpha1.UpgradeStateProgressing {
+			reqLogger.Info("Rolling out Keycloak version", "From", keycloak.Status.CurrentVersion, "To", version)
+		}
+		keycloak.Status.UpgradeState = codewindv1alpha1.UpgradeStateProgressing
+	default:
+		reqLogger.Info("Keycloak version rollout complete", "Version", version)
+		keycloak.Status.CurrentVersion = version
+		keycloak.Status.UpgradeState = codewindv1alpha1.UpgradeStateComplete
+	}
+}
@@ -0,0 +1,75 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloak
+
+import (
+	"context"
+	"time"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/security"
+	"github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rotateKeycloakCredentials : regenerates the Keycloak admin password once spec.rotateCredentialsAt is due. The new
+// password is saved in the admin secret before Keycloak is changed, so an interrupted rotation resumes with the same
+// password. Returns how long to wait for a rotation that is not due yet
+func (r *ReconcileKeycloak) rotateKeycloakCredentials(reqLogger logr.Logger, keycloak *codewindv1alpha1.Keycloak, deploymentOptions DeploymentOptionsKeycloak, httpClient util.HTTPClient) (time.Duration, error) {
+	due, wait := util.CredentialRotationDue(keycloak.Spec.RotateCredentialsAt, keycloak.Status.CredentialsRotatedAt, time.Now())
+	if !due {
+		return wait, nil
+	}
+
+	secretUser := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentOptions.KeycloakSecretsName, Namespace: keycloak.Namespace}, secretUser)
+	if err != nil {
+		return 0, err
+	}
+	if secretUser.Data == nil {
+		secretUser.Data = map[string][]byte{}
+	}
+	newPassword := string(secretUser.Data[defaults.KeycloakAdminPasswordPendingKey])
+	if newPassword == "" {
+		newPassword, err = util.GeneratePassword(defaults.KeycloakAdminPasswordLength)
+		if err != nil {
+			return 0, err
+		}
+		secretUser.Data[defaults.KeycloakAdminPasswordPendingKey] = []byte(newPassword)
+		err = r.client.Update(context.TODO(), secretUser)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	reqLogger.Info("Rotating the Keycloak admin password", "Namespace", keycloak.Namespace, "Name", keycloak.Name)
+	err = security.RotateKeycloakAdminPassword(httpClient, deploymentOptions.KeycloakAccessURL, string(secretUser.Data["keycloak-admin-user"]), string(secretUser.Data["keycloak-admin-password"]), newPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	secretUser.Data["keycloak-admin-password"] = []byte(newPassword)
+	delete(secretUser.Data, defaults.KeycloakAdminPasswordPendingKey)
+	err = r.client.Update(context.TODO(), secretUser)
+	if err != nil {
+		return 0, err
+	}
+
+	rotatedAt := metav1.Now()
+	keycloak.Status.CredentialsRotatedAt = &rotatedAt
+	r.recorder.Event(keycloak, corev1.EventTypeNormal, eventReasonCredentialsRotated, "Rotated the admin password")
+	return 0, nil
+}
@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloakbackup
+
+import (
+	"context"
+	"errors"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/platform"
+	"github.com/eclipse/codewind-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// keycloakConnection : location and admin credentials of an operator managed Keycloak
+type keycloakConnection struct {
+	AuthURL    string
+	Realm      string
+	AdminUser  string
+	AdminPass  string
+	HTTPClient util.HTTPClient
+}
+
+// getKeycloakConnection : resolves the Keycloak CR named keycloakName in the given namespace, its admin credentials
+// and the realm to use, defaulting to the operator config map defaultRealm
+func getKeycloakConnection(currentClient client.Client, namespace string, keycloakName string, realm string) (*keycloakConnection, error) {
+	keycloak := &codewindv1alpha1.Keycloak{}
+	err := currentClient.Get(context.TODO(), types.NamespacedName{Name: keycloakName, Namespace: namespace}, keycloak)
+	if err != nil {
+		return nil, errors.New("Unable to find Keycloak '" + keycloakName + "': " + err.Error())
+	}
+	authID := keycloak.GetAnnotations()["authID"]
+	if authID == "" || keycloak.Status.AccessURL == "" {
+		return nil, errors.New("Keycloak '" + keycloakName + "' has not been deployed yet")
+	}
+
+	secretUser := &corev1.Secret{}
+	err = currentClient.Get(context.TODO(), types.NamespacedName{Name: "secret-keycloak-user-" + authID, Namespace: namespace}, secretUser)
+	if err != nil {
+		return nil, errors.New("Unable to read the Keycloak admin credentials: " + err.Error())
+	}
+
+	operatorConfigMap := &corev1.ConfigMap{}
+	err = currentClient.Get(context.TODO(), types.NamespacedName{Name: defaults.OperatorConfigMapName, Namespace: util.GetOperatorNamespace()}, operatorConfigMap)
+	if err != nil {
+		return nil, errors.New("Unable to read the operator config map: " + err.Error())
+	}
+	if realm == "" {
+		realm = util.ValueOrDefault(operatorConfigMap.Data["defaultRealm"], defaults.CodewindAuthRealm)
+	}
+
+	profile, _ := platform.Current(operatorConfigMap.Data)
+	tlsSecretName := types.NamespacedName{Name: "secret-keycloak-tls-" + authID, Namespace: namespace}
+	httpClient, err := util.NewManagedKeycloakHTTPClient(currentClient, util.HTTPClientSettingsFromConfigMap(operatorConfigMap.Data), defaults.OperatorCASecretName, defaults.CABundleConfigMapName, tlsSecretName, profile.OpenShift)
+	if err != nil {
+		return nil, errors.New("Unable to configure the Keycloak HTTP client: " + err.Error())
+	}
+
+	return &keycloakConnection{
+		AuthURL:    keycloak.Status.AccessURL,
+		Realm:      realm,
+		AdminUser:  string(secretUser.Data["keycloak-admin-user"]),
+		AdminPass:  string(secretUser.Data["keycloak-admin-password"]),
+		HTTPClient: httpClient,
+	}, nil
+}
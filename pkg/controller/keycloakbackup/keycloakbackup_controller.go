@@ -0,0 +1,236 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloakbackup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+	"github.com/eclipse/codewind-operator/pkg/security"
+	"github.com/eclipse/codewind-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_keycloakbackup")
+
+// Add : creates the KeycloakBackup and KeycloakRestore Controllers and adds them to the Manager. The Manager will set
+// fields on the Controllers and Start them when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	err := add(mgr, &ReconcileKeycloakBackup{client: mgr.GetClient(), scheme: mgr.GetScheme()})
+	if err != nil {
+		return err
+	}
+	return addRestore(mgr, &ReconcileKeycloakRestore{client: mgr.GetClient(), scheme: mgr.GetScheme()})
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+
+	// Create a new controller
+	c, err := controller.New("keycloakbackup-controller", mgr, controller.Options{Reconciler: metrics.InstrumentReconciler("keycloakbackup-controller", r)})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource KeycloakBackup, status updates are ignored
+	err = c.Watch(&source.Kind{Type: &codewindv1alpha1.KeycloakBackup{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// blank assignment to verify that ReconcileKeycloakBackup implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileKeycloakBackup{}
+
+// ReconcileKeycloakBackup reconciles a KeycloakBackup object
+type ReconcileKeycloakBackup struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile : Exports the realm of a Keycloak into a Secret, once when the KeycloakBackup has no schedule, else each
+// time the schedule is due. Older backups beyond spec.retain are deleted
+func (r *ReconcileKeycloakBackup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakBackup")
+
+	// Fetch the KeycloakBackup instance
+	backup := &codewindv1alpha1.KeycloakBackup{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, backup)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			// KeycloakBackup resource not found. Ignoring since object must be deleted
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get KeycloakBackup.", "")
+		return reconcile.Result{}, err
+	}
+
+	now := time.Now().UTC()
+	var nextBackupTime time.Time
+	if backup.Spec.Schedule == "" {
+		// A single backup, taken once
+		if backup.Status.LastBackupTime != nil {
+			return reconcile.Result{}, nil
+		}
+	} else {
+		schedule, err := util.ParseCronSchedule(backup.Spec.Schedule)
+		if err != nil {
+			reqLogger.Error(err, "Invalid backup schedule, waiting for the CR to be corrected", "Schedule", backup.Spec.Schedule)
+			return r.updateBackupStatus(backup, codewindv1alpha1.BackupPhaseFailed, "Invalid schedule: "+err.Error(), reconcile.Result{})
+		}
+		lastScheduleTime := backup.CreationTimestamp.Time
+		if backup.Status.LastScheduleTime != nil {
+			lastScheduleTime = backup.Status.LastScheduleTime.Time
+		}
+		nextBackupTime = schedule.Next(lastScheduleTime.UTC())
+		if nextBackupTime.IsZero() {
+			return r.updateBackupStatus(backup, codewindv1alpha1.BackupPhaseFailed, "The schedule never runs", reconcile.Result{})
+		}
+		if now.Before(nextBackupTime) {
+			// Not due yet, wait for the next scheduled time
+			backup.Status.NextBackupTime = &metav1.Time{Time: nextBackupTime}
+			return r.updateBackupStatus(backup, backup.Status.Phase, backup.Status.Message, reconcile.Result{RequeueAfter: nextBackupTime.Sub(now)})
+		}
+		// Backups missed while the operator was not running are not caught up, the schedule restarts from now
+		nextBackupTime = schedule.Next(now)
+	}
+
+	secretName, err := r.takeBackup(reqLogger, backup, now)
+	if err != nil {
+		reqLogger.Error(err, "Keycloak backup failed", "Keycloak", backup.Spec.KeycloakDeployment)
+		return r.updateBackupStatus(backup, codewindv1alpha1.BackupPhaseFailed, err.Error(), reconcile.Result{RequeueAfter: defaults.KeycloakBackupRetryInterval})
+	}
+
+	backupTime := metav1.NewTime(now)
+	backup.Status.LastBackupTime = &backupTime
+	backup.Status.LastBackupSecret = secretName
+	result := reconcile.Result{}
+	if backup.Spec.Schedule != "" {
+		lastScheduleTime := metav1.NewTime(now.Truncate(time.Minute))
+		backup.Status.LastScheduleTime = &lastScheduleTime
+		backup.Status.NextBackupTime = &metav1.Time{Time: nextBackupTime}
+		result.RequeueAfter = nextBackupTime.Sub(now)
+	}
+
+	err = r.pruneBackups(reqLogger, backup)
+	if err != nil {
+		reqLogger.Error(err, "Failed to delete old backups", "Namespace", backup.Namespace, "Name", backup.Name)
+	}
+	return r.updateBackupStatus(backup, codewindv1alpha1.BackupPhaseCompleted, "Realm exported to secret "+secretName, result)
+}
+
+// takeBackup : exports the realm and saves it in a new secret, returning the name of the secret
+func (r *ReconcileKeycloakBackup) takeBackup(reqLogger logr.Logger, backup *codewindv1alpha1.KeycloakBackup, now time.Time) (string, error) {
+	connection, err := getKeycloakConnection(r.client, backup.Namespace, backup.Spec.KeycloakDeployment, backup.Spec.Realm)
+	if err != nil {
+		return "", err
+	}
+	realmData, err := security.ExportCodewindRealm(connection.HTTPClient, connection.AuthURL, connection.Realm, connection.AdminUser, connection.AdminPass)
+	if err != nil {
+		return "", err
+	}
+	secret := r.secretForKeycloakBackup(backup, realmData, now)
+	reqLogger.Info("Saving Keycloak backup", "Namespace", secret.Namespace, "Name", secret.Name, "Realm", connection.Realm)
+	err = r.client.Create(context.TODO(), secret)
+	if err != nil {
+		return "", err
+	}
+	return secret.Name, nil
+}
+
+// pruneBackups : deletes the oldest backup secrets of a KeycloakBackup beyond the number to retain
+func (r *ReconcileKeycloakBackup) pruneBackups(reqLogger logr.Logger, backup *codewindv1alpha1.KeycloakBackup) error {
+	retain := int(backup.Spec.Retain)
+	if retain < 1 {
+		retain = defaults.KeycloakBackupRetain
+	}
+	secrets := &corev1.SecretList{}
+	opts := []client.ListOption{
+		client.InNamespace(backup.Namespace),
+		client.MatchingLabels{defaults.KeycloakBackupLabel: backup.Name},
+	}
+	err := r.client.List(context.TODO(), secrets, opts...)
+	if err != nil {
+		return err
+	}
+	if len(secrets.Items) <= retain {
+		return nil
+	}
+	// Newest first
+	sort.Slice(secrets.Items, func(i, j int) bool {
+		return secrets.Items[j].CreationTimestamp.Before(&secrets.Items[i].CreationTimestamp)
+	})
+	for i := retain; i < len(secrets.Items); i++ {
+		reqLogger.Info("Deleting old Keycloak backup", "Namespace", secrets.Items[i].Namespace, "Name", secrets.Items[i].Name)
+		err = r.client.Delete(context.TODO(), &secrets.Items[i])
+		if err != nil && !k8serr.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretForKeycloakBackup : a secret holding one export of the realm. The secret has no owner so backups outlive the
+// KeycloakBackup and the Keycloak they were taken from
+func (r *ReconcileKeycloakBackup) secretForKeycloakBackup(backup *codewindv1alpha1.KeycloakBackup, realmData []byte, now time.Time) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backup.Name + "-" + now.Format("20060102-150405"),
+			Namespace: backup.Namespace,
+			Labels: map[string]string{
+				"app":                        defaults.PrefixCodewindKeycloak,
+				defaults.KeycloakBackupLabel: backup.Name,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			defaults.KeycloakBackupDataKey: realmData,
+		},
+	}
+}
+
+// updateBackupStatus : saves the phase and message of the latest backup and returns the given result
+func (r *ReconcileKeycloakBackup) updateBackupStatus(backup *codewindv1alpha1.KeycloakBackup, phase codewindv1alpha1.BackupPhase, message string, result reconcile.Result) (reconcile.Result, error) {
+	if phase == "" {
+		phase = codewindv1alpha1.BackupPhasePending
+	}
+	backup.Status.Phase = phase
+	backup.Status.Message = message
+	err := r.client.Status().Update(context.TODO(), backup)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,164 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package keycloakbackup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+	"github.com/eclipse/codewind-operator/pkg/security"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// addRestore adds a new Controller to mgr with r as the reconcile.Reconciler
+func addRestore(mgr manager.Manager, r reconcile.Reconciler) error {
+
+	// Create a new controller
+	c, err := controller.New("keycloakrestore-controller", mgr, controller.Options{Reconciler: metrics.InstrumentReconciler("keycloakrestore-controller", r)})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource KeycloakRestore, status updates are ignored
+	err = c.Watch(&source.Kind{Type: &codewindv1alpha1.KeycloakRestore{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// blank assignment to verify that ReconcileKeycloakRestore implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileKeycloakRestore{}
+
+// ReconcileKeycloakRestore reconciles a KeycloakRestore object
+type ReconcileKeycloakRestore struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile : Imports a backup taken by a KeycloakBackup into a Keycloak. The import runs once for each generation of
+// the spec, it is retried until it succeeds or the maximum number of attempts is reached
+func (r *ReconcileKeycloakRestore) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling KeycloakRestore")
+
+	// Fetch the KeycloakRestore instance
+	restore := &codewindv1alpha1.KeycloakRestore{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, restore)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			// KeycloakRestore resource not found. Ignoring since object must be deleted
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		reqLogger.Error(err, "Failed to get KeycloakRestore.", "")
+		return reconcile.Result{}, err
+	}
+
+	// An edited spec is a new restore, run again with a fresh count of attempts. Restores recorded before the
+	// generation was tracked are not run again
+	generationChanged := restore.Status.ObservedGeneration != restore.Generation
+	if generationChanged {
+		if restore.Status.ObservedGeneration != 0 {
+			reqLogger.Info("KeycloakRestore changed, restarting the restore", "Phase", restore.Status.Phase, "Attempts", restore.Status.Attempts)
+			restore.Status.Phase = ""
+			restore.Status.Attempts = 0
+			restore.Status.Message = ""
+			restore.Status.RestoreTime = nil
+		}
+		restore.Status.ObservedGeneration = restore.Generation
+	}
+
+	if restore.Status.Phase == codewindv1alpha1.BackupPhaseCompleted || restore.Status.Phase == codewindv1alpha1.BackupPhaseFailed {
+		if generationChanged {
+			return reconcile.Result{}, r.client.Status().Update(context.TODO(), restore)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	err = r.restoreBackup(reqLogger, restore)
+	if err != nil {
+		restore.Status.Attempts++
+		reqLogger.Error(err, "Keycloak restore failed", "Keycloak", restore.Spec.KeycloakDeployment, "Attempts", restore.Status.Attempts)
+		restore.Status.Message = err.Error()
+		result := reconcile.Result{RequeueAfter: defaults.KeycloakRestoreRetryInterval}
+		restore.Status.Phase = codewindv1alpha1.BackupPhasePending
+		if restore.Status.Attempts >= defaults.KeycloakRestoreMaxAttempts {
+			restore.Status.Phase = codewindv1alpha1.BackupPhaseFailed
+			restore.Status.Message = "Giving up after " + strconv.Itoa(int(restore.Status.Attempts)) + " attempts: " + err.Error()
+			result = reconcile.Result{}
+		}
+		err = r.client.Status().Update(context.TODO(), restore)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return result, nil
+	}
+
+	restoreTime := metav1.Now()
+	restore.Status.RestoreTime = &restoreTime
+	restore.Status.Phase = codewindv1alpha1.BackupPhaseCompleted
+	restore.Status.Message = "Realm imported from secret " + restore.Spec.BackupSecret
+	err = r.client.Status().Update(context.TODO(), restore)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// restoreBackup : reads the backup secret and imports it into the realm of the restore, else the realm the backup
+// was taken from
+func (r *ReconcileKeycloakRestore) restoreBackup(reqLogger logr.Logger, restore *codewindv1alpha1.KeycloakRestore) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: restore.Spec.BackupSecret, Namespace: restore.Namespace}, secret)
+	if err != nil {
+		return errors.New("Unable to read backup secret '" + restore.Spec.BackupSecret + "': " + err.Error())
+	}
+	realmData := secret.Data[defaults.KeycloakBackupDataKey]
+	if len(realmData) == 0 {
+		return errors.New("Backup secret '" + restore.Spec.BackupSecret + "' has no " + defaults.KeycloakBackupDataKey)
+	}
+
+	realm := restore.Spec.Realm
+	if realm == "" {
+		backup := security.RealmBackup{}
+		err = json.Unmarshal(realmData, &backup)
+		if err != nil {
+			return errors.New("Unable to parse backup secret '" + restore.Spec.BackupSecret + "': " + err.Error())
+		}
+		realm = backup.Realm
+	}
+
+	connection, err := getKeycloakConnection(r.client, restore.Namespace, restore.Spec.KeycloakDeployment, realm)
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Restoring Keycloak backup", "Secret", restore.Spec.BackupSecret, "Realm", connection.Realm)
+	return security.ImportCodewindRealm(connection.HTTPClient, connection.AuthURL, connection.Realm, connection.AdminUser, connection.AdminPass, realmData)
+}
@@ -0,0 +1,117 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// certificateExpiry : expiry time of the TLS certificates generated by the operator, one series per CR
+var certificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "codewind_operator_certificate_expiry_timestamp_seconds",
+		Help: "Expiry time of the TLS certificate generated for a Codewind or Keycloak, in seconds since the epoch",
+	},
+	[]string{"namespace", "kind", "name"},
+)
+
+// reconcileDuration : time taken by each reconcile, by controller
+var reconcileDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "codewind_operator_reconcile_duration_seconds",
+		Help:    "Time taken to reconcile a Codewind operator resource, in seconds",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	},
+	[]string{"controller"},
+)
+
+// reconcileErrors : reconciles that returned an error, by controller
+var reconcileErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "codewind_operator_reconcile_errors_total",
+		Help: "Number of reconciles of a Codewind operator resource that returned an error",
+	},
+	[]string{"controller"},
+)
+
+// keycloakAdminDuration : time taken by each Keycloak admin operation, such as registering a Codewind client
+var keycloakAdminDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "codewind_operator_keycloak_admin_call_duration_seconds",
+		Help:    "Time taken by a Keycloak admin operation, including the wait for Keycloak to respond, in seconds",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	},
+	[]string{"call"},
+)
+
+// keycloakAdminFailures : failed Keycloak admin operations, by operation and by the op of the security error
+var keycloakAdminFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "codewind_operator_keycloak_admin_call_failures_total",
+		Help: "Number of failed Keycloak admin operations, by the kind of error that stopped them",
+	},
+	[]string{"call", "op"},
+)
+
+// serviceWaitDuration : time spent waiting for a service such as Keycloak to respond
+var serviceWaitDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "codewind_operator_service_wait_duration_seconds",
+		Help:    "Time spent waiting for a service to respond before calling it, in seconds",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+	},
+	[]string{"result"},
+)
+
+func init() {
+	// Served by the manager on its metrics endpoint
+	crmetrics.Registry.MustRegister(certificateExpiry, reconcileDuration, reconcileErrors, keycloakAdminDuration, keycloakAdminFailures, serviceWaitDuration)
+}
+
+// SetCertificateExpiry : records the expiry time of the certificate of a Codewind or Keycloak
+func SetCertificateExpiry(namespace string, kind string, name string, notAfter time.Time) {
+	certificateExpiry.WithLabelValues(namespace, kind, name).Set(float64(notAfter.Unix()))
+}
+
+// DeleteCertificateExpiry : stops reporting the certificate of a deleted Codewind or Keycloak
+func DeleteCertificateExpiry(namespace string, kind string, name string) {
+	certificateExpiry.DeleteLabelValues(namespace, kind, name)
+}
+
+// ObserveReconcile : records the duration of a reconcile and whether it returned an error
+func ObserveReconcile(controller string, duration time.Duration, err error) {
+	reconcileDuration.WithLabelValues(controller).Observe(duration.Seconds())
+	if err != nil {
+		reconcileErrors.WithLabelValues(controller).Inc()
+	}
+}
+
+// ObserveKeycloakAdminCall : records the duration of a Keycloak admin operation. op is the security error operation
+// that stopped a failed call, and is empty when the call succeeded
+func ObserveKeycloakAdminCall(call string, duration time.Duration, op string) {
+	keycloakAdminDuration.WithLabelValues(call).Observe(duration.Seconds())
+	if op != "" {
+		keycloakAdminFailures.WithLabelValues(call, op).Inc()
+	}
+}
+
+// ObserveServiceWait : records the time spent waiting for a service, and whether it responded in time
+func ObserveServiceWait(duration time.Duration, ready bool) {
+	result := "ready"
+	if !ready {
+		result = "timeout"
+	}
+	serviceWaitDuration.WithLabelValues(result).Observe(duration.Seconds())
+}
@@ -0,0 +1,74 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"context"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// codewindPhases : phases reported by the collector, so each series exists even when no instance is in that phase
+var codewindPhases = []codewindv1alpha1.CodewindPhase{
+	codewindv1alpha1.CodewindPhasePending,
+	codewindv1alpha1.CodewindPhaseStarting,
+	codewindv1alpha1.CodewindPhaseRunning,
+	codewindv1alpha1.CodewindPhaseFailed,
+	codewindv1alpha1.CodewindPhaseSuspended,
+}
+
+var codewindPhaseDesc = prometheus.NewDesc(
+	"codewind_operator_codewind_instances",
+	"Number of Codewind instances watched by the operator, by phase",
+	[]string{"phase"}, nil,
+)
+
+// codewindPhaseCollector : counts the Codewind instances in each phase when the metrics are scraped
+type codewindPhaseCollector struct {
+	client client.Client
+}
+
+// RegisterCodewindPhaseCollector : reports the number of Codewind instances by phase, read through the client of the
+// manager so that scrapes are served from its cache
+func RegisterCodewindPhaseCollector(c client.Client) error {
+	return crmetrics.Registry.Register(&codewindPhaseCollector{client: c})
+}
+
+// Describe : implements prometheus.Collector
+func (collector *codewindPhaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- codewindPhaseDesc
+}
+
+// Collect : implements prometheus.Collector
+func (collector *codewindPhaseCollector) Collect(ch chan<- prometheus.Metric) {
+	codewinds := &codewindv1alpha1.CodewindList{}
+	err := collector.client.List(context.TODO(), codewinds)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(codewindPhaseDesc, err)
+		return
+	}
+	counts := make(map[codewindv1alpha1.CodewindPhase]int)
+	for _, codewind := range codewinds.Items {
+		phase := codewind.Status.Phase
+		// Instances that have not been reconciled yet have no phase
+		if phase == "" {
+			phase = codewindv1alpha1.CodewindPhasePending
+		}
+		counts[phase]++
+	}
+	for _, phase := range codewindPhases {
+		ch <- prometheus.MustNewConstMetric(codewindPhaseDesc, prometheus.GaugeValue, float64(counts[phase]), string(phase))
+	}
+}
@@ -0,0 +1,38 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// instrumentedReconciler : records the duration and the errors of each reconcile of a controller
+type instrumentedReconciler struct {
+	controller string
+	reconciler reconcile.Reconciler
+}
+
+// InstrumentReconciler : wraps the reconciler of a controller so that its reconciles are reported in the operator
+// metrics under the name of the controller
+func InstrumentReconciler(controller string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return &instrumentedReconciler{controller: controller, reconciler: reconciler}
+}
+
+// Reconcile : implements reconcile.Reconciler
+func (r *instrumentedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := r.reconciler.Reconcile(request)
+	ObserveReconcile(r.controller, time.Since(start), err)
+	return result, err
+}
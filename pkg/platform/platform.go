@@ -0,0 +1,184 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("platform")
+
+// ConfigMapKey : key of the operator config map that overrides the detected platform
+const ConfigMapKey = "platform"
+
+// Profile : storage, ingress and security defaults of a Kubernetes platform
+type Profile struct {
+	// Name : value of the platform key of the operator config map that selects this profile
+	Name string
+
+	// Description : name of the platform reported in the operator log
+	Description string
+
+	// OpenShift : the platform serves the OpenShift APIs. Codewind and Keycloak are exposed with routes, PFE is
+	// given the ODO cluster roles, and Keycloak is not verified against the operator CA as routes serve the
+	// router certificate
+	OpenShift bool
+
+	// CodewindStorageClass : storage class of the PFE PVC, used when the cluster has it
+	CodewindStorageClass string
+
+	// KeycloakStorageClass : storage class of the Keycloak PVC, used when the cluster has it
+	KeycloakStorageClass string
+
+	// IngressClass : value of the kubernetes.io/ingress.class annotation of the ingresses, empty leaves it unset
+	IngressClass string
+
+	// KeycloakFSGroup : file system group of the Keycloak pod so that it can write to block storage owned by root,
+	// nil leaves it to the platform
+	KeycloakFSGroup *int64
+
+	// detect : true when the cluster runs this platform
+	detect func(cluster *clusterInfo) bool
+}
+
+// clusterInfo : facts about the cluster gathered once at startup to select a profile
+type clusterInfo struct {
+	apiGroups      map[string]bool
+	storageClasses map[string]bool
+	provisioners   map[string]bool
+}
+
+var (
+	mutex    sync.RWMutex
+	cluster  = &clusterInfo{}
+	detected *Profile
+)
+
+// Detect : selects the profile of the cluster the operator runs on. Called once at startup, falls back to the
+// vanilla Kubernetes profile when the cluster cannot be inspected
+func Detect(cfg *rest.Config) (*Profile, error) {
+	info, err := inspectCluster(cfg)
+	mutex.Lock()
+	defer mutex.Unlock()
+	if err != nil {
+		detected = vanilla
+		return detected, err
+	}
+	cluster = info
+	detected = vanilla
+	for _, profile := range profiles {
+		if profile.detect(cluster) {
+			detected = profile
+			break
+		}
+	}
+	return detected.resolve(cluster), nil
+}
+
+// Current : profile named by the platform key of the operator config map, else the detected profile. An unknown
+// name returns the detected profile with an error
+func Current(configMapData map[string]string) (*Profile, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	current := detected
+	if current == nil {
+		current = vanilla
+	}
+	name := strings.TrimSpace(configMapData[ConfigMapKey])
+	if name == "" {
+		return current.resolve(cluster), nil
+	}
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile.resolve(cluster), nil
+		}
+	}
+	return current.resolve(cluster), fmt.Errorf("unknown platform %q, expected one of %s", name, strings.Join(Names(), ", "))
+}
+
+// Names : names of the supported platforms
+func Names() []string {
+	names := []string{}
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+// resolve : copy of the profile with the storage classes the cluster does not have cleared, so that the PVCs use
+// the default storage class of the cluster instead
+func (p *Profile) resolve(cluster *clusterInfo) *Profile {
+	resolved := *p
+	if !cluster.storageClasses[resolved.CodewindStorageClass] {
+		resolved.CodewindStorageClass = ""
+	}
+	if !cluster.storageClasses[resolved.KeycloakStorageClass] {
+		resolved.KeycloakStorageClass = ""
+	}
+	return &resolved
+}
+
+// inspectCluster : reads the API groups served by the cluster and its storage classes
+func inspectCluster(cfg *rest.Config) (*clusterInfo, error) {
+	info := &clusterInfo{
+		apiGroups:      map[string]bool{},
+		storageClasses: map[string]bool{},
+		provisioners:   map[string]bool{},
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	apiList, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, apiGroup := range apiList.Groups {
+		info.apiGroups[apiGroup.Name] = true
+	}
+
+	// The cache of the manager is not started yet so the storage classes are read with a direct client
+	directClient, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	storageClasses := &storagev1.StorageClassList{}
+	err = directClient.List(context.TODO(), storageClasses)
+	if err != nil {
+		return nil, err
+	}
+	for _, storageClass := range storageClasses.Items {
+		info.storageClasses[storageClass.Name] = true
+		info.provisioners[storageClass.Provisioner] = true
+	}
+	log.Info("Inspected the cluster", "APIGroups", len(info.apiGroups), "StorageClasses", len(info.storageClasses))
+	return info, nil
+}
+
+// hasAny : true when any of the names is in the set
+func hasAny(set map[string]bool, names ...string) bool {
+	for _, name := range names {
+		if set[name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package platform
+
+import (
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+)
+
+var keycloakFSGroup = int64(defaults.KeycloakFSGroup)
+
+// vanilla : any other Kubernetes cluster, the PVCs use the default storage class of the cluster
+var vanilla = &Profile{
+	Name:         "vanilla",
+	Description:  "Kubernetes",
+	IngressClass: defaults.IngressClassNginx,
+	detect:       func(cluster *clusterInfo) bool { return true },
+}
+
+// profiles : supported platforms in the order they are detected, the first match is used. Support for a new
+// platform is added here
+var profiles = []*Profile{
+	{
+		Name:                 "roks",
+		Description:          "Red Hat OpenShift on IBM Cloud",
+		OpenShift:            true,
+		CodewindStorageClass: defaults.ROKSStorageClass,
+		KeycloakStorageClass: defaults.ROKSStorageClassGID,
+		detect: func(cluster *clusterInfo) bool {
+			return cluster.apiGroups["route.openshift.io"] && cluster.storageClasses[defaults.ROKSStorageClass]
+		},
+	},
+	{
+		Name:        "openshift4",
+		Description: "OpenShift 4",
+		OpenShift:   true,
+		detect: func(cluster *clusterInfo) bool {
+			return cluster.apiGroups["config.openshift.io"]
+		},
+	},
+	{
+		Name:        "openshift311",
+		Description: "OpenShift 3.11",
+		OpenShift:   true,
+		detect: func(cluster *clusterInfo) bool {
+			return cluster.apiGroups["route.openshift.io"]
+		},
+	},
+	{
+		Name:                 "iks",
+		Description:          "IBM Cloud Kubernetes Service",
+		CodewindStorageClass: defaults.ROKSStorageClass,
+		KeycloakStorageClass: defaults.ROKSStorageClassGID,
+		IngressClass:         defaults.IngressClassNginx,
+		detect: func(cluster *clusterInfo) bool {
+			return cluster.storageClasses[defaults.ROKSStorageClass]
+		},
+	},
+	{
+		Name:                 "eks",
+		Description:          "Amazon Elastic Kubernetes Service",
+		CodewindStorageClass: defaults.EKSStorageClassRWX,
+		IngressClass:         defaults.IngressClassNginx,
+		KeycloakFSGroup:      &keycloakFSGroup,
+		detect: func(cluster *clusterInfo) bool {
+			return hasAny(cluster.provisioners, "kubernetes.io/aws-ebs", "ebs.csi.aws.com", "efs.csi.aws.com")
+		},
+	},
+	{
+		Name:                 "gke",
+		Description:          "Google Kubernetes Engine",
+		CodewindStorageClass: defaults.GKEStorageClassRWX,
+		IngressClass:         defaults.IngressClassNginx,
+		KeycloakFSGroup:      &keycloakFSGroup,
+		detect: func(cluster *clusterInfo) bool {
+			return hasAny(cluster.provisioners, "kubernetes.io/gce-pd", "pd.csi.storage.gke.io", "filestore.csi.storage.gke.io")
+		},
+	},
+	{
+		Name:                 "aks",
+		Description:          "Azure Kubernetes Service",
+		CodewindStorageClass: defaults.AKSStorageClassRWX,
+		IngressClass:         defaults.IngressClassNginx,
+		KeycloakFSGroup:      &keycloakFSGroup,
+		detect: func(cluster *clusterInfo) bool {
+			return hasAny(cluster.provisioners, "kubernetes.io/azure-disk", "disk.csi.azure.com", "kubernetes.io/azure-file", "file.csi.azure.com")
+		},
+	},
+	{
+		Name:         "kind",
+		Description:  "kind",
+		IngressClass: defaults.IngressClassNginx,
+		detect: func(cluster *clusterInfo) bool {
+			return cluster.provisioners["rancher.io/local-path"]
+		},
+	},
+	{
+		Name:         "minikube",
+		Description:  "minikube",
+		IngressClass: defaults.IngressClassNginx,
+		detect: func(cluster *clusterInfo) bool {
+			return cluster.provisioners["k8s.io/minikube-hostpath"]
+		},
+	},
+	vanilla,
+}
@@ -0,0 +1,273 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eclipse/codewind-operator/pkg/util"
+)
+
+// RealmBackup : clients, roles, groups and users of a realm, in the format accepted by the Keycloak partial import
+type RealmBackup struct {
+	Realm   string                   `json:"realm"`
+	Clients []map[string]interface{} `json:"clients,omitempty"`
+	Roles   map[string]interface{}   `json:"roles,omitempty"`
+	Groups  []map[string]interface{} `json:"groups,omitempty"`
+	Users   []map[string]interface{} `json:"users,omitempty"`
+}
+
+// maskedSecret : value Keycloak returns in place of client secrets in a partial export
+const maskedSecret = "**********"
+
+// userPageSize : number of users requested per page when exporting users
+const userPageSize = 100
+
+// ExportCodewindRealm : exports the clients, including their secrets, the roles, the groups and the users of a realm.
+// User passwords cannot be read through the admin API and are not part of the export
+func ExportCodewindRealm(httpClient util.HTTPClient, authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string) (backupData []byte, err error) {
+	call := startAdminCall(callExportRealm)
+	defer func() { call.end(err) }()
+	var keycloakConfig KeycloakConfiguration
+	keycloakConfig.RealmName = realmName
+	keycloakConfig.AuthURL = authURL
+	keycloakConfig.KeycloakAdminPassword = keycloakAdminPass
+	keycloakConfig.KeycloakAdminUsername = keycloakAdminUser
+
+	tokens, secErr := SecAuthenticate(httpClient, &keycloakConfig)
+	if secErr != nil {
+		return nil, call.fail(secErr)
+	}
+
+	log.Info("Exporting realm", "realm", realmName, "URL", authURL)
+	backup, secErr := SecRealmPartialExport(httpClient, &keycloakConfig, tokens.AccessToken)
+	if secErr != nil {
+		return nil, call.fail(secErr)
+	}
+
+	// The partial export masks client secrets, read them back so restored gatekeepers can still authenticate
+	for _, client := range backup.Clients {
+		clientID, _ := client["id"].(string)
+		if client["secret"] != maskedSecret || clientID == "" {
+			continue
+		}
+		secret, secErr := secClientSecretByID(httpClient, &keycloakConfig, tokens.AccessToken, clientID)
+		if secErr != nil {
+			return nil, call.fail(secErr)
+		}
+		client["secret"] = secret
+	}
+
+	backup.Users, secErr = SecUserExport(httpClient, &keycloakConfig, tokens.AccessToken)
+	if secErr != nil {
+		return nil, call.fail(secErr)
+	}
+	log.Info("Exported realm", "realm", realmName, "clients", len(backup.Clients), "users", len(backup.Users))
+
+	backupData, err = json.Marshal(backup)
+	if err != nil {
+		return nil, call.fail(&SecError{errOpResponseFormat, err, err.Error()})
+	}
+	return backupData, nil
+}
+
+// ImportCodewindRealm : creates the realm when it does not exist, then imports the clients, roles, groups and users of
+// a backup. Entries that already exist in the realm are left unchanged
+func ImportCodewindRealm(httpClient util.HTTPClient, authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string, backupData []byte) (err error) {
+	call := startAdminCall(callImportRealm)
+	defer func() { call.end(err) }()
+	backup := RealmBackup{}
+	err = json.Unmarshal(backupData, &backup)
+	if err != nil {
+		return call.fail(&SecError{errOpResponseFormat, errors.New("Unable to parse the realm backup: " + err.Error()), textUnableToParse})
+	}
+
+	var keycloakConfig KeycloakConfiguration
+	keycloakConfig.RealmName = realmName
+	keycloakConfig.AuthURL = authURL
+	keycloakConfig.KeycloakAdminPassword = keycloakAdminPass
+	keycloakConfig.KeycloakAdminUsername = keycloakAdminUser
+
+	tokens, secErr := SecAuthenticate(httpClient, &keycloakConfig)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+
+	secErr = configureKeycloakRealm(httpClient, &keycloakConfig, tokens.AccessToken)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+
+	log.Info("Importing realm", "realm", realmName, "clients", len(backup.Clients), "users", len(backup.Users))
+	secErr = SecRealmPartialImport(httpClient, &keycloakConfig, tokens.AccessToken, &backup)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+	return nil
+}
+
+// SecRealmPartialExport : Export the clients, roles and groups of a realm
+func SecRealmPartialExport(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string) (*RealmBackup, *SecError) {
+	url := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName + "/partial-export?exportClients=true&exportGroupsAndRoles=true"
+	body, secErr := secAdminRequest(httpClient, "POST", url, accessToken, nil, http.StatusOK)
+	if secErr != nil {
+		return nil, secErr
+	}
+	backup := RealmBackup{}
+	err := json.Unmarshal(body, &backup)
+	if err != nil {
+		return nil, &SecError{errOpResponseFormat, err, textUnableToParse}
+	}
+	backup.Realm = keycloakConfig.RealmName
+	return &backup, nil
+}
+
+// SecRealmPartialImport : Import clients, roles, groups and users into a realm, skipping those that already exist
+func SecRealmPartialImport(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string, backup *RealmBackup) *SecError {
+	type PartialImport struct {
+		IfResourceExists string                   `json:"ifResourceExists"`
+		Clients          []map[string]interface{} `json:"clients,omitempty"`
+		Roles            map[string]interface{}   `json:"roles,omitempty"`
+		Groups           []map[string]interface{} `json:"groups,omitempty"`
+		Users            []map[string]interface{} `json:"users,omitempty"`
+	}
+	partialImport := PartialImport{
+		IfResourceExists: "SKIP",
+		Clients:          backup.Clients,
+		Roles:            backup.Roles,
+		Groups:           backup.Groups,
+		Users:            backup.Users,
+	}
+	jsonImport, err := json.Marshal(partialImport)
+	if err != nil {
+		return &SecError{errOpResponseFormat, err, err.Error()}
+	}
+	url := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName + "/partialImport"
+	_, secErr := secAdminRequest(httpClient, "POST", url, accessToken, strings.NewReader(string(jsonImport)), http.StatusOK)
+	return secErr
+}
+
+// SecUserExport : Export every user of a realm together with the names of their realm roles and the paths of their groups
+func SecUserExport(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string) ([]map[string]interface{}, *SecError) {
+	realmURL := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName
+	users := []map[string]interface{}{}
+	for first := 0; ; first += userPageSize {
+		body, secErr := secAdminRequest(httpClient, "GET", realmURL+"/users?first="+strconv.Itoa(first)+"&max="+strconv.Itoa(userPageSize), accessToken, nil, http.StatusOK)
+		if secErr != nil {
+			return nil, secErr
+		}
+		page := []map[string]interface{}{}
+		err := json.Unmarshal(body, &page)
+		if err != nil {
+			return nil, &SecError{errOpResponseFormat, err, textUnableToParse}
+		}
+		for _, user := range page {
+			userID, _ := user["id"].(string)
+
+			// realm role mappings
+			body, secErr = secAdminRequest(httpClient, "GET", realmURL+"/users/"+userID+"/role-mappings/realm", accessToken, nil, http.StatusOK)
+			if secErr != nil {
+				return nil, secErr
+			}
+			roles := []Role{}
+			err = json.Unmarshal(body, &roles)
+			if err != nil {
+				return nil, &SecError{errOpResponseFormat, err, textUnableToParse}
+			}
+			realmRoles := []string{}
+			for _, role := range roles {
+				realmRoles = append(realmRoles, role.Name)
+			}
+			user["realmRoles"] = realmRoles
+
+			// group membership
+			body, secErr = secAdminRequest(httpClient, "GET", realmURL+"/users/"+userID+"/groups", accessToken, nil, http.StatusOK)
+			if secErr != nil {
+				return nil, secErr
+			}
+			groups := []struct {
+				Path string `json:"path"`
+			}{}
+			err = json.Unmarshal(body, &groups)
+			if err != nil {
+				return nil, &SecError{errOpResponseFormat, err, textUnableToParse}
+			}
+			groupPaths := []string{}
+			for _, group := range groups {
+				groupPaths = append(groupPaths, group.Path)
+			}
+			user["groups"] = groupPaths
+
+			// fields that are only meaningful to the exporting server
+			delete(user, "access")
+			delete(user, "createdTimestamp")
+			users = append(users, user)
+		}
+		if len(page) < userPageSize {
+			return users, nil
+		}
+	}
+}
+
+// secClientSecretByID : Retrieve the secret of a client from its internal ID
+func secClientSecretByID(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string, clientID string) (string, *SecError) {
+	url := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName + "/clients/" + clientID + "/client-secret"
+	body, secErr := secAdminRequest(httpClient, "GET", url, accessToken, nil, http.StatusOK)
+	if secErr != nil {
+		return "", secErr
+	}
+	registeredClientSecret := RegisteredClientSecret{}
+	err := json.Unmarshal(body, &registeredClientSecret)
+	if err != nil {
+		return "", &SecError{errOpResponseFormat, err, textUnableToParse}
+	}
+	return registeredClientSecret.Secret, nil
+}
+
+// secAdminRequest : Send a request to the Keycloak admin API and return the response body when the expected status is returned
+func secAdminRequest(httpClient util.HTTPClient, method string, url string, accessToken string, payload io.Reader, expectedStatus int) ([]byte, *SecError) {
+	req, err := http.NewRequest(method, url, payload)
+	if err != nil {
+		return nil, &SecError{errOpConnection, err, err.Error()}
+	}
+	if payload != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+	req.Header.Add("Cache-Control", "no-cache")
+	req.Header.Add("cache-control", "no-cache")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, &SecError{errOpConnection, err, err.Error()}
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, &SecError{errOpResponse, err, err.Error()}
+	}
+	if res.StatusCode != expectedStatus {
+		keycloakAPIError := parseKeycloakError(string(body), res.StatusCode)
+		if keycloakAPIError.ErrorDescription == "" {
+			keycloakAPIError.ErrorDescription = "HTTP " + res.Status
+		}
+		kcError := errors.New(keycloakAPIError.ErrorDescription)
+		return nil, &SecError{errOpResponse, kcError, kcError.Error()}
+	}
+	return body, nil
+}
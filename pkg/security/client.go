@@ -77,7 +77,7 @@ func SecClientCreate(httpClient util.HTTPClient, keycloakConfig *KeycloakConfigu
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 
 	// send request
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return &SecError{errOpConnection, err, err.Error()}
 	}
@@ -104,7 +104,7 @@ func SecClientGet(httpClient util.HTTPClient, keycloakConfig *KeycloakConfigurat
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 	req.Header.Add("Cache-Control", "no-cache")
 	req.Header.Add("cache-control", "no-cache")
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, &SecError{errOpConnection, err, err.Error()}
 	}
@@ -155,7 +155,7 @@ func SecClientGetSecret(httpClient util.HTTPClient, keycloakConfig *KeycloakConf
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 	req.Header.Add("cache-control", "no-cache")
 	req.Header.Add("Cache-Control", "no-cache")
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, &SecError{errOpConnection, err, err.Error()}
 	}
@@ -217,3 +217,38 @@ func SecClientAppendURL(httpClient util.HTTPClient, keycloakConfig *KeycloakConf
 	defer res.Body.Close()
 	return nil
 }
+
+// SecClientDelete : Delete the client from Keycloak. A client that does not exist is not an error
+func SecClientDelete(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string) *SecError {
+
+	// lookup the internal ID of the client
+	registeredClient, secErr := SecClientGet(httpClient, keycloakConfig, accessToken)
+	if secErr != nil {
+		return secErr
+	}
+	if registeredClient == nil || registeredClient.ID == "" {
+		return nil
+	}
+
+	// build REST request
+	url := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName + "/clients/" + registeredClient.ID
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return &SecError{errOpConnection, err, err.Error()}
+	}
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+	req.Header.Add("Cache-Control", "no-cache")
+	req.Header.Add("cache-control", "no-cache")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return &SecError{errOpConnection, err, err.Error()}
+	}
+	defer res.Body.Close()
+
+	// handle HTTP status codes (success returns status code StatusNoContent)
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusNotFound {
+		err = errors.New("HTTP " + res.Status)
+		return &SecError{errOpDelete, err, err.Error()}
+	}
+	return nil
+}
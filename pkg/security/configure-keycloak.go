@@ -20,8 +20,9 @@ import (
 
 // AddCodewindToKeycloak : sets up Keycloak with a realm, client and user
 // Returns a clientKey or an error
-func AddCodewindToKeycloak(workspaceID string, authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string, gatekeeperPublicURL string, devUsername string, clientName string) (string, error) {
-
+func AddCodewindToKeycloak(httpClient util.HTTPClient, workspaceID string, authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string, gatekeeperPublicURL string, devUsername string, clientName string) (clientKey string, err error) {
+	call := startAdminCall(callAddCodewind)
+	defer func() { call.end(err) }()
 	var keycloakConfig KeycloakConfiguration
 	keycloakConfig.RealmName = realmName
 	keycloakConfig.AuthURL = authURL
@@ -34,52 +35,102 @@ func AddCodewindToKeycloak(workspaceID string, authURL string, realmName string,
 
 	// Wait for the Keycloak service to respond
 	log.Info("Waiting for Keycloak to start", "URL", keycloakConfig.AuthURL)
-	startErr := util.WaitForService(keycloakConfig.AuthURL, 200, 500)
+	startErr := util.WaitForService(httpClient, keycloakConfig.AuthURL, 200, 500)
 	if startErr != nil {
-		return "", errors.New("Keycloak did not start in a reasonable about of time")
+		return "", call.fail(&SecError{errOpConnection, errors.New("Keycloak did not start in a reasonable about of time"), textAuthIsDown})
 	}
 
-	tokens, secErr := SecAuthenticate(http.DefaultClient, &keycloakConfig)
+	tokens, secErr := SecAuthenticate(httpClient, &keycloakConfig)
 	if secErr != nil {
-		return "", secErr.Err
+		return "", call.fail(secErr)
 	}
 
-	secErr = configureKeycloakRealm(http.DefaultClient, &keycloakConfig, tokens.AccessToken)
+	secErr = configureKeycloakRealm(httpClient, &keycloakConfig, tokens.AccessToken)
 	if secErr != nil {
-		return "", secErr.Err
+		return "", call.fail(secErr)
 	}
 
-	secErr = configureKeycloakClient(http.DefaultClient, &keycloakConfig, tokens.AccessToken)
+	secErr = configureKeycloakClient(httpClient, &keycloakConfig, tokens.AccessToken)
 	if secErr != nil {
-		return "", secErr.Err
+		return "", call.fail(secErr)
 	}
 
-	secErr = configureKeycloakAccessRole(http.DefaultClient, &keycloakConfig, tokens.AccessToken, "codewind-"+keycloakConfig.WorkspaceID)
+	secErr = configureKeycloakAccessRole(httpClient, &keycloakConfig, tokens.AccessToken, "codewind-"+keycloakConfig.WorkspaceID)
 	if secErr != nil {
-		return "", secErr.Err
+		return "", call.fail(secErr)
 	}
 
-	secErr = configureKeycloakUser(http.DefaultClient, &keycloakConfig, tokens.AccessToken)
+	secErr = configureKeycloakUser(httpClient, &keycloakConfig, tokens.AccessToken)
 	if secErr != nil {
-		return "", secErr.Err
+		return "", call.fail(secErr)
 	}
 
-	secErr = grantUserAccessToDeployment(http.DefaultClient, &keycloakConfig, tokens.AccessToken)
+	secErr = grantUserAccessToDeployment(httpClient, &keycloakConfig, tokens.AccessToken)
 	if secErr != nil {
-		return "", secErr.Err
+		return "", call.fail(secErr)
 	}
 
-	registeredSecret, secErr := fetchClientSecret(http.DefaultClient, &keycloakConfig, tokens.AccessToken)
+	registeredSecret, secErr := fetchClientSecret(httpClient, &keycloakConfig, tokens.AccessToken)
 	if secErr != nil {
-		return "", secErr.Err
+		return "", call.fail(secErr)
 	}
 
 	return registeredSecret.Secret, nil
 
 }
 
+// RemoveCodewindFromKeycloak : removes the client, access role and user role grant of a deployment from Keycloak.
+// Entries that no longer exist are skipped so the cleanup can be safely retried
+func RemoveCodewindFromKeycloak(httpClient util.HTTPClient, workspaceID string, authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string, devUsername string, clientName string) (err error) {
+	call := startAdminCall(callRemoveCodewind)
+	defer func() { call.end(err) }()
+	var keycloakConfig KeycloakConfiguration
+	keycloakConfig.RealmName = realmName
+	keycloakConfig.AuthURL = authURL
+	keycloakConfig.WorkspaceID = workspaceID
+	keycloakConfig.KeycloakAdminPassword = keycloakAdminPass
+	keycloakConfig.KeycloakAdminUsername = keycloakAdminUser
+	keycloakConfig.DevUsername = devUsername
+	keycloakConfig.ClientName = clientName
+
+	// Keep the wait short, the caller retries the cleanup on failure
+	log.Info("RemoveCodewind: Checking Keycloak service is responding", "URL", keycloakConfig.AuthURL)
+	startErr := util.WaitForService(httpClient, keycloakConfig.AuthURL, 200, 10)
+	if startErr != nil {
+		return call.fail(&SecError{errOpConnection, errors.New("Keycloak is not responding"), textAuthIsDown})
+	}
+
+	tokens, secErr := SecAuthenticate(httpClient, &keycloakConfig)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+
+	accessRoleName := "codewind-" + keycloakConfig.WorkspaceID
+
+	log.Info("Revoking user access to deployment", "Username", keycloakConfig.DevUsername, "Workspace", keycloakConfig.WorkspaceID)
+	secErr = SecUserRemoveRole(httpClient, &keycloakConfig, tokens.AccessToken, accessRoleName)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+
+	log.Info("Removing access role from realm", "rolename", accessRoleName, "realmName", keycloakConfig.RealmName)
+	secErr = SecRoleDelete(httpClient, &keycloakConfig, tokens.AccessToken, accessRoleName)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+
+	log.Info("Removing Keycloak client", "name", keycloakConfig.ClientName)
+	secErr = SecClientDelete(httpClient, &keycloakConfig, tokens.AccessToken)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+	return nil
+}
+
 // AddCodewindRealmToKeycloak : Installs a keycloak realm
-func AddCodewindRealmToKeycloak(authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string) error {
+func AddCodewindRealmToKeycloak(httpClient util.HTTPClient, authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string) (err error) {
+	call := startAdminCall(callAddRealm)
+	defer func() { call.end(err) }()
 	var keycloakConfig KeycloakConfiguration
 	keycloakConfig.RealmName = realmName
 	keycloakConfig.AuthURL = authURL
@@ -88,19 +139,19 @@ func AddCodewindRealmToKeycloak(authURL string, realmName string, keycloakAdminU
 
 	// Wait for the Keycloak service to respond
 	log.Info("AddRealm: Checking Keycloak service is responding", "realm", keycloakConfig.RealmName, "URL", keycloakConfig.AuthURL)
-	startErr := util.WaitForService(keycloakConfig.AuthURL, 200, 500)
+	startErr := util.WaitForService(httpClient, keycloakConfig.AuthURL, 200, 500)
 	if startErr != nil {
-		return errors.New("Keycloak did not start in a reasonable about of time")
+		return call.fail(&SecError{errOpConnection, errors.New("Keycloak did not start in a reasonable about of time"), textAuthIsDown})
 	}
 
-	tokens, secErr := SecAuthenticate(http.DefaultClient, &keycloakConfig)
+	tokens, secErr := SecAuthenticate(httpClient, &keycloakConfig)
 	if secErr != nil {
-		return secErr.Err
+		return call.fail(secErr)
 	}
 
-	secErr = configureKeycloakRealm(http.DefaultClient, &keycloakConfig, tokens.AccessToken)
+	secErr = configureKeycloakRealm(httpClient, &keycloakConfig, tokens.AccessToken)
 	if secErr != nil {
-		return secErr.Err
+		return call.fail(secErr)
 	}
 	return nil
 }
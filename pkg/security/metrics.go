@@ -0,0 +1,56 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package security
+
+import (
+	"time"
+
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+)
+
+// Names of the Keycloak admin operations reported in the operator metrics
+const (
+	callAddCodewind         = "add_codewind"
+	callRemoveCodewind      = "remove_codewind"
+	callAddRealm            = "add_realm"
+	callRotateAdminPassword = "rotate_admin_password"
+	callRotateClientSecret  = "rotate_client_secret"
+	callExportRealm         = "export_realm"
+	callImportRealm         = "import_realm"
+)
+
+// adminCall : times one Keycloak admin operation and records the SecError operation that stopped it
+type adminCall struct {
+	name  string
+	start time.Time
+	op    string
+}
+
+// startAdminCall : starts timing a Keycloak admin operation
+func startAdminCall(name string) *adminCall {
+	return &adminCall{name: name, start: time.Now()}
+}
+
+// fail : records the operation of the error that stopped the call and returns the underlying error
+func (call *adminCall) fail(secErr *SecError) error {
+	call.op = secErr.Op
+	return secErr.Err
+}
+
+// end : reports the duration of the call, and its failure when err is set
+func (call *adminCall) end(err error) {
+	op := ""
+	if err != nil {
+		op = call.op
+	}
+	metrics.ObserveKeycloakAdminCall(call.name, time.Since(call.start), op)
+}
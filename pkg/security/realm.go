@@ -74,7 +74,7 @@ func SecRealmGet(httpClient util.HTTPClient, keycloakConfig *KeycloakConfigurati
 // SecRealmCreate : Create a new realm in Keycloak
 func SecRealmCreate(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string) *SecError {
 
-	themeLoginName, themeAccountName, secErr := GetSuggestedThemes(keycloakConfig.AuthURL, accessToken)
+	themeLoginName, themeAccountName, secErr := GetSuggestedThemes(httpClient, keycloakConfig.AuthURL, accessToken)
 	if secErr != nil {
 		return secErr
 	}
@@ -116,7 +116,7 @@ func SecRealmCreate(httpClient util.HTTPClient, keycloakConfig *KeycloakConfigur
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 
 	// send request
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return &SecError{errOpConnection, err, err.Error()}
 	}
@@ -64,7 +64,7 @@ func SecRoleCreate(httpClient utils.HTTPClient, keycloakConfig *KeycloakConfigur
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 
 	// send request
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return &SecError{errOpConnection, err, err.Error()}, res.StatusCode
 	}
@@ -85,6 +85,34 @@ func SecRoleCreate(httpClient utils.HTTPClient, keycloakConfig *KeycloakConfigur
 	return nil, res.StatusCode
 }
 
+// SecRoleDelete : Delete a role from Keycloak. A role that does not exist is not an error
+func SecRoleDelete(httpClient utils.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string, roleName string) *SecError {
+
+	// build REST request
+	url := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName + "/roles/" + roleName
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return &SecError{errOpConnection, err, err.Error()}
+	}
+	req.Header.Add("Cache-Control", "no-cache")
+	req.Header.Add("cache-control", "no-cache")
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+
+	// send request
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return &SecError{errOpConnection, err, err.Error()}
+	}
+	defer res.Body.Close()
+
+	// handle HTTP status codes (success returns status code StatusNoContent)
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusNotFound {
+		secErr := errors.New("HTTP " + res.Status)
+		return &SecError{errOpDelete, secErr, secErr.Error()}
+	}
+	return nil
+}
+
 func getRoleByName(httpClient utils.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string, roleName string) (*Role, *SecError) {
 
 	requestedRole := roleName
@@ -102,12 +130,16 @@ func getRoleByName(httpClient utils.HTTPClient, keycloakConfig *KeycloakConfigur
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 
 	// send request
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, &SecError{errOpConnection, err, err.Error()}
 	}
 
 	// check we received a valid response
+	if res.StatusCode == http.StatusNotFound {
+		errNotFound := errors.New("Role " + requestedRole + " not found in realm")
+		return nil, &SecError{errOpNotFound, errNotFound, errNotFound.Error()}
+	}
 	if res.StatusCode != http.StatusOK {
 		unableToReadErr := errors.New("Bad response")
 		return nil, &SecError{errOpConnection, unableToReadErr, unableToReadErr.Error()}
@@ -0,0 +1,123 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/eclipse/codewind-operator/pkg/util"
+)
+
+// RotateKeycloakAdminPassword : replaces the password of the Keycloak admin user in the master realm. Returns
+// without a change when the new password is already in use, so a rotation interrupted after the password was reset
+// can safely be repeated
+func RotateKeycloakAdminPassword(httpClient util.HTTPClient, authURL string, keycloakAdminUser string, keycloakAdminPass string, newPassword string) (err error) {
+	call := startAdminCall(callRotateAdminPassword)
+	defer func() { call.end(err) }()
+	var keycloakConfig KeycloakConfiguration
+	keycloakConfig.RealmName = "master"
+	keycloakConfig.AuthURL = authURL
+	keycloakConfig.KeycloakAdminUsername = keycloakAdminUser
+	keycloakConfig.KeycloakAdminPassword = newPassword
+
+	_, secErr := SecAuthenticate(httpClient, &keycloakConfig)
+	if secErr == nil {
+		log.Info("Keycloak admin password already rotated", "URL", authURL)
+		return nil
+	}
+
+	keycloakConfig.KeycloakAdminPassword = keycloakAdminPass
+	tokens, secErr := SecAuthenticate(httpClient, &keycloakConfig)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+
+	realmURL := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName
+	body, secErr := secAdminRequest(httpClient, "GET", realmURL+"/users?username="+url.QueryEscape(keycloakAdminUser), tokens.AccessToken, nil, http.StatusOK)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+	registeredUsers := []RegisteredUser{}
+	err = json.Unmarshal(body, &registeredUsers)
+	if err != nil {
+		return call.fail(&SecError{errOpResponseFormat, err, textUnableToParse})
+	}
+	// The username query matches substrings, pick the exact user
+	userID := ""
+	for _, user := range registeredUsers {
+		if user.Username == strings.ToLower(keycloakAdminUser) {
+			userID = user.ID
+		}
+	}
+	if userID == "" {
+		return call.fail(&SecError{errOpNotFound, errors.New(textUserNotFound), textUserNotFound})
+	}
+
+	credential := struct {
+		Type      string `json:"type"`
+		Value     string `json:"value"`
+		Temporary bool   `json:"temporary"`
+	}{"password", newPassword, false}
+	jsonCredential, err := json.Marshal(credential)
+	if err != nil {
+		return call.fail(&SecError{errOpResponseFormat, err, err.Error()})
+	}
+	_, secErr = secAdminRequest(httpClient, "PUT", realmURL+"/users/"+userID+"/reset-password", tokens.AccessToken, strings.NewReader(string(jsonCredential)), http.StatusNoContent)
+	if secErr != nil {
+		return call.fail(secErr)
+	}
+	log.Info("Keycloak admin password rotated", "URL", authURL)
+	return nil
+}
+
+// RotateCodewindClientSecret : regenerates the secret of a Codewind client and returns the new secret
+func RotateCodewindClientSecret(httpClient util.HTTPClient, authURL string, realmName string, keycloakAdminUser string, keycloakAdminPass string, clientName string) (clientSecret string, err error) {
+	call := startAdminCall(callRotateClientSecret)
+	defer func() { call.end(err) }()
+	var keycloakConfig KeycloakConfiguration
+	keycloakConfig.RealmName = realmName
+	keycloakConfig.AuthURL = authURL
+	keycloakConfig.KeycloakAdminPassword = keycloakAdminPass
+	keycloakConfig.KeycloakAdminUsername = keycloakAdminUser
+	keycloakConfig.ClientName = clientName
+
+	tokens, secErr := SecAuthenticate(httpClient, &keycloakConfig)
+	if secErr != nil {
+		return "", call.fail(secErr)
+	}
+
+	registeredClient, secErr := SecClientGet(httpClient, &keycloakConfig, tokens.AccessToken)
+	if secErr != nil {
+		return "", call.fail(secErr)
+	}
+	if registeredClient == nil {
+		notFound := errors.New("Client " + clientName + " not found in realm " + realmName)
+		return "", call.fail(&SecError{errOpNotFound, notFound, notFound.Error()})
+	}
+
+	secretURL := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName + "/clients/" + registeredClient.ID + "/client-secret"
+	body, secErr := secAdminRequest(httpClient, "POST", secretURL, tokens.AccessToken, nil, http.StatusOK)
+	if secErr != nil {
+		return "", call.fail(secErr)
+	}
+	registeredClientSecret := RegisteredClientSecret{}
+	err = json.Unmarshal(body, &registeredClientSecret)
+	if err != nil {
+		return "", call.fail(&SecError{errOpResponseFormat, err, textUnableToParse})
+	}
+	log.Info("Client secret rotated", "realm", realmName, "client", clientName)
+	return registeredClientSecret.Secret, nil
+}
@@ -43,6 +43,7 @@ const (
 	errOpResponseFormat = "sec_bodyparser"      // Parse errors
 	errOpNotFound       = "sec_notfound"        // No matching search results
 	errOpCreate         = "sec_create"          // Create failed
+	errOpDelete         = "sec_delete"          // Delete failed
 	errOpPassword       = "sec_passwordcontent" // Password formatting
 	errOpHostname       = "sec_badhostname"     // Bad hostname / url
 	errOpConConfig      = "sec_con_config"      // Connection configuration errors
@@ -17,6 +17,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+
+	"github.com/eclipse/codewind-operator/pkg/util"
 )
 
 // RegisteredTheme : A Keycloak theme
@@ -41,7 +43,7 @@ type ServerInfo struct {
 }
 
 // GetServerInfo - fetch Keycloak server info
-func GetServerInfo(keycloakHostname string, accesstoken string) (*ServerInfo, *SecError) {
+func GetServerInfo(httpClient util.HTTPClient, keycloakHostname string, accesstoken string) (*ServerInfo, *SecError) {
 
 	// build REST request
 	url := keycloakHostname + "/auth/admin/serverinfo"
@@ -55,7 +57,7 @@ func GetServerInfo(keycloakHostname string, accesstoken string) (*ServerInfo, *S
 	req.Header.Add("cache-control", "no-cache")
 
 	// send request
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, &SecError{errOpConnection, err, err.Error()}
 	}
@@ -87,8 +89,8 @@ func GetServerInfo(keycloakHostname string, accesstoken string) (*ServerInfo, *S
 
 // GetSuggestedThemes - Recommends the Codewind theme, else Che, else keycloak default
 // Returns the loginTheme, accountTheme, optionalError
-func GetSuggestedThemes(keycloakHostname string, accesstoken string) (string, string, *SecError) {
-	serverInfo, secErr := GetServerInfo(keycloakHostname, accesstoken)
+func GetSuggestedThemes(httpClient util.HTTPClient, keycloakHostname string, accesstoken string) (string, string, *SecError) {
+	serverInfo, secErr := GetServerInfo(httpClient, keycloakHostname, accesstoken)
 	if secErr != nil {
 		return "", "", secErr
 	}
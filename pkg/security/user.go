@@ -47,7 +47,7 @@ func SecUserGet(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguratio
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 	req.Header.Add("cache-control", "no-cache")
 	req.Header.Add("Cache-Control", "no-cache")
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, &SecError{errOpConnection, err, err.Error()}
 	}
@@ -119,7 +119,7 @@ func SecUserAddRole(httpClient util.HTTPClient, keycloakConfig *KeycloakConfigur
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("cache-control", "no-cache")
 	req.Header.Add("Cache-Control", "no-cache")
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return &SecError{errOpConnection, err, err.Error()}
 	}
@@ -132,3 +132,62 @@ func SecUserAddRole(httpClient util.HTTPClient, keycloakConfig *KeycloakConfigur
 
 	return nil
 }
+
+// SecUserRemoveRole : Removes a role from a specified user. A missing user or role is not an error
+func SecUserRemoveRole(httpClient util.HTTPClient, keycloakConfig *KeycloakConfiguration, accessToken string, roleName string) *SecError {
+
+	// lookup an existing user
+	log.Info("Looking up user", "Username", keycloakConfig.DevUsername)
+	registeredUser, secErr := SecUserGet(httpClient, keycloakConfig, accessToken)
+	if secErr != nil {
+		if secErr.Op == errOpNotFound {
+			return nil
+		}
+		return secErr
+	}
+
+	// get the existing role
+	existingRole, secErr := getRoleByName(httpClient, keycloakConfig, accessToken, roleName)
+	if secErr != nil {
+		if secErr.Op == errOpNotFound {
+			return nil
+		}
+		return secErr
+	}
+
+	// build REST request
+	log.Info("Removing role from user", "role", existingRole.Name, "userID", registeredUser.ID)
+	url := keycloakConfig.AuthURL + "/auth/admin/realms/" + keycloakConfig.RealmName + "/users/" + registeredUser.ID + "/role-mappings/realm"
+
+	type PayloadRole struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	listOfRoles := []PayloadRole{{ID: existingRole.ID, Name: existingRole.Name}}
+	jsonRolesToRemove, err := json.Marshal(listOfRoles)
+	payload := strings.NewReader(string(jsonRolesToRemove))
+
+	req, err := http.NewRequest("DELETE", url, payload)
+	if err != nil {
+		return &SecError{errOpConnection, err, err.Error()}
+	}
+
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("cache-control", "no-cache")
+	req.Header.Add("Cache-Control", "no-cache")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return &SecError{errOpConnection, err, err.Error()}
+	}
+	defer res.Body.Close()
+
+	// handle HTTP status codes (success returns status code StatusNoContent)
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusNotFound {
+		errDelete := errors.New(res.Status)
+		return &SecError{errOpDelete, errDelete, errDelete.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// multiNamespaceCache : watches namespaced resources in a list of namespaces, and cluster scoped resources such as
+// cluster roles and storage classes across the cluster
+type multiNamespaceCache struct {
+	cache.Cache
+	clusterScoped cache.Cache
+	scheme        *runtime.Scheme
+	mapper        meta.RESTMapper
+}
+
+// MultiNamespaceCacheBuilder : returns a cache watching several namespaces. Unlike the controller-runtime
+// multi-namespace cache it can also read cluster scoped resources
+func MultiNamespaceCacheBuilder(namespaces []string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		namespaced, err := cache.MultiNamespacedCacheBuilder(namespaces)(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		opts.Namespace = ""
+		clusterScoped, err := cache.New(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &multiNamespaceCache{Cache: namespaced, clusterScoped: clusterScoped, scheme: opts.Scheme, mapper: opts.Mapper}, nil
+	}
+}
+
+// isClusterScoped : returns true when the kind of obj is not namespaced
+func (c *multiNamespaceCache) isClusterScoped(obj runtime.Object) (bool, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return false, err
+	}
+	return c.isClusterScopedKind(gvk)
+}
+
+// isClusterScopedKind : returns true when a kind is not namespaced
+func (c *multiNamespaceCache) isClusterScopedKind(gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+// Get : implements client.Reader
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	clusterScoped, err := c.isClusterScoped(obj)
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.clusterScoped.Get(ctx, key, obj)
+	}
+	return c.Cache.Get(ctx, key, obj)
+}
+
+// List : implements client.Reader
+func (c *multiNamespaceCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	clusterScoped, err := c.isClusterScoped(list)
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.clusterScoped.List(ctx, list, opts...)
+	}
+	return c.Cache.List(ctx, list, opts...)
+}
+
+// GetInformer : implements cache.Informers
+func (c *multiNamespaceCache) GetInformer(obj runtime.Object) (cache.Informer, error) {
+	clusterScoped, err := c.isClusterScoped(obj)
+	if err != nil {
+		return nil, err
+	}
+	if clusterScoped {
+		return c.clusterScoped.GetInformer(obj)
+	}
+	return c.Cache.GetInformer(obj)
+}
+
+// GetInformerForKind : implements cache.Informers
+func (c *multiNamespaceCache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.Informer, error) {
+	clusterScoped, err := c.isClusterScopedKind(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if clusterScoped {
+		return c.clusterScoped.GetInformerForKind(gvk)
+	}
+	return c.Cache.GetInformerForKind(gvk)
+}
+
+// Start : implements cache.Informers
+func (c *multiNamespaceCache) Start(stopCh <-chan struct{}) error {
+	var log = logf.Log.WithName("controller_codewind_cacheutils.go")
+	go func() {
+		err := c.clusterScoped.Start(stopCh)
+		if err != nil {
+			log.Error(err, "Cluster scoped cache stopped")
+		}
+	}()
+	return c.Cache.Start(stopCh)
+}
+
+// WaitForCacheSync : implements cache.Informers
+func (c *multiNamespaceCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return c.clusterScoped.WaitForCacheSync(stop) && c.Cache.WaitForCacheSync(stop)
+}
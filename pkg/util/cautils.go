@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// caTitle : subject of the generated operator CA
+const caTitle = "Codewind Operator CA"
+
+// EnsureCertificateAuthority : returns the operator CA kept in secretName in the operator namespace, generating it on
+// first use, and publishes its certificate in the ca.crt key of bundleName in the same namespace
+func EnsureCertificateAuthority(c client.Client, secretName string, bundleName string) (*CertificateAuthority, error) {
+	var log = logf.Log.WithName("controller_codewind_cautils.go")
+	namespace := GetOperatorNamespace()
+
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+	if err != nil && k8serr.IsNotFound(err) {
+		pemCAKey, pemCACert, err := GenerateCertificateAuthority(caTitle)
+		if err != nil {
+			return nil, err
+		}
+		// The CA outlives every Keycloak and Codewind instance so the secret has no owner
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+				Labels:    map[string]string{"app": "codewind-operator"},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": []byte(pemCACert),
+				"tls.key": []byte(pemCAKey),
+			},
+		}
+		log.Info("Creating the operator CA", "Namespace", namespace, "Name", secretName)
+		err = c.Create(context.TODO(), secret)
+		if err != nil && k8serr.IsAlreadyExists(err) {
+			// Another controller created the CA first
+			err = c.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	ca, err := ParseCertificateAuthority(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = PublishCABundle(c, ca, bundleName, namespace, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// PublishCABundle : writes the certificate of the CA to the ca.crt key of a config map, creating it when needed. When
+// an owner is given it is added to the owners of the config map, which is removed once every owner is deleted
+func PublishCABundle(c client.Client, ca *CertificateAuthority, name string, namespace string, owner *metav1.OwnerReference) error {
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, configMap)
+	if err != nil && k8serr.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"app": "codewind-operator"},
+			},
+			Data: map[string]string{"ca.crt": ca.CertificatePEM},
+		}
+		if owner != nil {
+			configMap.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		err = c.Create(context.TODO(), configMap)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	changed := false
+	if configMap.Data["ca.crt"] != ca.CertificatePEM {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data["ca.crt"] = ca.CertificatePEM
+		changed = true
+	}
+	if owner != nil {
+		found := false
+		for _, ref := range configMap.OwnerReferences {
+			if ref.UID == owner.UID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			configMap.OwnerReferences = append(configMap.OwnerReferences, *owner)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return c.Update(context.TODO(), configMap)
+}
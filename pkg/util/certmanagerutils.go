@@ -0,0 +1,76 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"time"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// certManagerGroup : API group of the cert-manager resources
+	certManagerGroup = "cert-manager.io"
+
+	// certManagerClusterIssuer : default kind of a cert-manager issuer
+	certManagerClusterIssuer = "ClusterIssuer"
+)
+
+// CertificateGVK : cert-manager Certificate resource, built as an unstructured object so the operator does not
+// depend on the cert-manager client libraries
+var CertificateGVK = schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: "Certificate"}
+
+// ResolveIssuer : returns the issuer set on a CR, else the one set in the operator config map, with the kind and
+// group defaulted. Returns nil when neither is set
+func ResolveIssuer(specIssuer *codewindv1alpha1.IssuerReference, configMapName string, configMapKind string) *codewindv1alpha1.IssuerReference {
+	issuer := codewindv1alpha1.IssuerReference{Name: configMapName, Kind: configMapKind}
+	if specIssuer != nil && specIssuer.Name != "" {
+		issuer = *specIssuer
+	}
+	if issuer.Name == "" {
+		return nil
+	}
+	issuer.Kind = ValueOrDefault(issuer.Kind, certManagerClusterIssuer)
+	issuer.Group = ValueOrDefault(issuer.Group, certManagerGroup)
+	return &issuer
+}
+
+// NewCertificate : returns an empty cert-manager Certificate, ready to be read from the cluster
+func NewCertificate() *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(CertificateGVK)
+	return certificate
+}
+
+// BuildCertificate : returns a cert-manager Certificate for a single host, issued into secretName. cert-manager
+// renews the certificate renewBefore its expiry
+func BuildCertificate(meta metav1.ObjectMeta, secretName string, dnsName string, issuer *codewindv1alpha1.IssuerReference, renewBefore time.Duration) *unstructured.Unstructured {
+	certificate := NewCertificate()
+	certificate.SetName(meta.Name)
+	certificate.SetNamespace(meta.Namespace)
+	certificate.SetLabels(meta.Labels)
+	certificate.Object["spec"] = map[string]interface{}{
+		"secretName":  secretName,
+		"commonName":  dnsName,
+		"dnsNames":    []interface{}{dnsName},
+		"renewBefore": renewBefore.String(),
+		"issuerRef": map[string]interface{}{
+			"name":  issuer.Name,
+			"kind":  issuer.Kind,
+			"group": issuer.Group,
+		},
+	}
+	return certificate
+}
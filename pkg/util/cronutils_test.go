@@ -0,0 +1,106 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"unknown macro", "@fortnightly"},
+		{"minute out of range", "60 * * * *"},
+		{"day of month out of range", "0 0 0 * *"},
+		{"month out of range", "0 0 1 13 *"},
+		{"day of week out of range", "0 0 * * 8"},
+		{"reversed range", "20-10 * * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"invalid step", "*/x * * * *"},
+		{"invalid value", "x * * * *"},
+		{"invalid range", "1-x * * * *"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCronSchedule(test.expression)
+			if err == nil {
+				t.Errorf("ParseCronSchedule(%q) returned no error", test.expression)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2020-01-01 is a Wednesday
+	at := func(year int, month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{"every minute", "* * * * *", at(2020, 1, 1, 0, 0), at(2020, 1, 1, 0, 1)},
+		{"seconds are truncated", "* * * * *", at(2020, 1, 1, 0, 0).Add(30 * time.Second), at(2020, 1, 1, 0, 1)},
+		{"star step", "*/15 * * * *", at(2020, 1, 1, 0, 7), at(2020, 1, 1, 0, 15)},
+		{"daily at a fixed hour", "0 2 * * *", at(2020, 1, 1, 3, 0), at(2020, 1, 2, 2, 0)},
+		{"list of hours", "30 6,18 * * *", at(2020, 1, 1, 7, 0), at(2020, 1, 1, 18, 30)},
+		{"range", "0 9-17 * * *", at(2020, 1, 1, 17, 0), at(2020, 1, 2, 9, 0)},
+
+		// a/step runs from a to the end of the field, a-b/step stops at b
+		{"start step", "10/20 * * * *", at(2020, 1, 1, 0, 0), at(2020, 1, 1, 0, 10)},
+		{"start step second value", "10/20 * * * *", at(2020, 1, 1, 0, 10), at(2020, 1, 1, 0, 30)},
+		{"start step wraps to the next hour", "10/20 * * * *", at(2020, 1, 1, 0, 50), at(2020, 1, 1, 1, 10)},
+		{"range step", "5-20/5 * * * *", at(2020, 1, 1, 0, 16), at(2020, 1, 1, 0, 20)},
+		{"range step stops at the end of the range", "5-20/5 * * * *", at(2020, 1, 1, 0, 20), at(2020, 1, 1, 1, 5)},
+
+		// Sunday is both 0 and 7
+		{"sunday as 0", "0 0 * * 0", at(2020, 1, 1, 0, 0), at(2020, 1, 5, 0, 0)},
+		{"sunday as 7", "0 0 * * 7", at(2020, 1, 1, 0, 0), at(2020, 1, 5, 0, 0)},
+		{"range ending at 7", "0 0 * * 6-7", at(2020, 1, 1, 0, 0), at(2020, 1, 4, 0, 0)},
+
+		// When both day fields are restricted either one matches, otherwise both must match
+		{"day of week only", "0 0 * * 1", at(2020, 1, 1, 0, 0), at(2020, 1, 6, 0, 0)},
+		{"day of month only", "0 0 13 * *", at(2020, 1, 1, 0, 0), at(2020, 1, 13, 0, 0)},
+		{"either day matches the day of week", "0 0 13 * 5", at(2020, 1, 1, 0, 0), at(2020, 1, 3, 0, 0)},
+		{"either day matches the day of month", "0 0 13 * 5", at(2020, 1, 11, 0, 0), at(2020, 1, 13, 0, 0)},
+		{"stepped day of month with any day of week", "0 0 */10 * *", at(2020, 1, 2, 0, 0), at(2020, 1, 11, 0, 0)},
+
+		{"next month", "0 0 1 * *", at(2020, 12, 15, 0, 0), at(2021, 1, 1, 0, 0)},
+		{"restricted month", "0 0 1 6 *", at(2020, 7, 1, 0, 0), at(2021, 6, 1, 0, 0)},
+		{"hourly macro", "@hourly", at(2020, 1, 1, 0, 30), at(2020, 1, 1, 1, 0)},
+		{"weekly macro", "@weekly", at(2020, 1, 1, 0, 0), at(2020, 1, 5, 0, 0)},
+		{"yearly macro", "@yearly", at(2020, 1, 1, 0, 0), at(2021, 1, 1, 0, 0)},
+
+		// Schedules are searched for five years
+		{"leap day within five years", "0 0 29 2 *", at(2020, 3, 1, 0, 0), at(2024, 2, 29, 0, 0)},
+		{"day that never occurs", "0 0 30 2 *", at(2020, 1, 1, 0, 0), time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(test.expression)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule(%q) returned %v", test.expression, err)
+			}
+			got := schedule.Next(test.from)
+			if !got.Equal(test.want) {
+				t.Errorf("Next(%v) of %q = %v, want %v", test.from, test.expression, got, test.want)
+			}
+		})
+	}
+}
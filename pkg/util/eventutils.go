@@ -0,0 +1,65 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the events recorded on Codewind and Keycloak instances by both controllers
+const (
+	// EventReasonCreated : a resource of the instance was created
+	EventReasonCreated = "Created"
+
+	// EventReasonCreateFailed : a resource of the instance could not be created
+	EventReasonCreateFailed = "CreateFailed"
+
+	// EventReasonStorageExpanding : the PVC of the instance is being expanded
+	EventReasonStorageExpanding = "StorageExpanding"
+
+	// EventReasonStorageResizeFailed : the requested size of the PVC of the instance cannot be applied
+	EventReasonStorageResizeFailed = "StorageResizeFailed"
+)
+
+// RecordCreate : records an event on the owner for a resource the operator created or failed to create. Resources
+// that already exist are not reported
+func RecordCreate(recorder record.EventRecorder, scheme *runtime.Scheme, owner runtime.Object, object runtime.Object, err error) {
+	if err != nil && k8serr.IsAlreadyExists(err) {
+		return
+	}
+	kind, name := ObjectKindAndName(scheme, object)
+	if err != nil {
+		recorder.Eventf(owner, corev1.EventTypeWarning, EventReasonCreateFailed, "Failed to create %s %s: %v", kind, name, err)
+		return
+	}
+	recorder.Eventf(owner, corev1.EventTypeNormal, EventReasonCreated, "Created %s %s", kind, name)
+}
+
+// RecordStorage : records an event on the owner when the expansion of its PVC starts, or when the requested size
+// cannot be applied. Unchanged states are not reported again
+func RecordStorage(recorder record.EventRecorder, owner runtime.Object, previous *codewindv1alpha1.StorageStatus, storage *codewindv1alpha1.StorageStatus, expanded bool) {
+	if expanded {
+		recorder.Event(owner, corev1.EventTypeNormal, EventReasonStorageExpanding, storage.Message)
+		return
+	}
+	if storage.ResizeState != codewindv1alpha1.StorageResizeStateUnsupported && storage.ResizeState != codewindv1alpha1.StorageResizeStateFailed {
+		return
+	}
+	if previous != nil && previous.ResizeState == storage.ResizeState && previous.Message == storage.Message {
+		return
+	}
+	recorder.Event(owner, corev1.EventTypeWarning, EventReasonStorageResizeFailed, storage.Message)
+}
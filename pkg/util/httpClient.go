@@ -12,29 +12,181 @@
 package util
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/eclipse/codewind-operator/pkg/metrics"
+	"golang.org/x/net/http/httpproxy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
+// maxCachedHTTPClients : number of distinct client settings kept before the cache is cleared
+const maxCachedHTTPClients = 32
+
 // HTTPClient : An net HTTP Client to simplify testing
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// HTTPClientSettings : TLS and proxy settings of the calls the operator makes to Keycloak, read from the operator
+// config map
+type HTTPClientSettings struct {
+	TLSVerify         string
+	CABundleSecret    string
+	CABundleConfigMap string
+	HTTPProxy         string
+	HTTPSProxy        string
+	NoProxy           string
+}
+
+// httpClientCache : clients built by NewKeycloakHTTPClient keyed by their settings, so that connections are reused
+var httpClientCache = struct {
+	sync.Mutex
+	clients map[string]*http.Client
+}{clients: map[string]*http.Client{}}
+
+// HTTPClientSettingsFromConfigMap : returns the Keycloak HTTP client settings held in the operator config map data
+func HTTPClientSettingsFromConfigMap(data map[string]string) HTTPClientSettings {
+	return HTTPClientSettings{
+		TLSVerify:         data["keycloakTLSVerify"],
+		CABundleSecret:    data["keycloakCABundleSecret"],
+		CABundleConfigMap: data["keycloakCABundleConfigMap"],
+		HTTPProxy:         data["httpProxy"],
+		HTTPSProxy:        data["httpsProxy"],
+		NoProxy:           data["noProxy"],
+	}
+}
+
+// NewKeycloakHTTPClient : returns the HTTP client used to call Keycloak. The client trusts the system certificates,
+// the ca.crt keys of the CA bundle secret and config map named by the settings in the operator namespace, and
+// extraCABundle. TLS is verified when the settings enable it or when an extraCABundle is given
+func NewKeycloakHTTPClient(c client.Client, settings HTTPClientSettings, extraCABundle []byte) (*http.Client, error) {
+	verify := len(extraCABundle) > 0
+	if settings.TLSVerify != "" {
+		tlsVerify, err := strconv.ParseBool(settings.TLSVerify)
+		if err != nil {
+			return nil, errors.New("Invalid keycloakTLSVerify '" + settings.TLSVerify + "' in the operator config map")
+		}
+		verify = verify || tlsVerify
+	}
+
+	caBundle := []byte{}
+	namespace := GetOperatorNamespace()
+	if settings.CABundleSecret != "" {
+		secret := &corev1.Secret{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: settings.CABundleSecret, Namespace: namespace}, secret)
+		if err != nil {
+			return nil, errors.New("Unable to read the Keycloak CA bundle secret: " + err.Error())
+		}
+		caBundle = append(caBundle, secret.Data["ca.crt"]...)
+		caBundle = append(caBundle, '\n')
+	}
+	if settings.CABundleConfigMap != "" {
+		configMap := &corev1.ConfigMap{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: settings.CABundleConfigMap, Namespace: namespace}, configMap)
+		if err != nil {
+			return nil, errors.New("Unable to read the Keycloak CA bundle config map: " + err.Error())
+		}
+		caBundle = append(caBundle, configMap.Data["ca.crt"]...)
+		caBundle = append(caBundle, '\n')
+	}
+	caBundle = append(caBundle, extraCABundle...)
+
+	bundleHash := sha256.Sum256(caBundle)
+	key := fmt.Sprintf("%v|%v|%v|%v|%v", verify, hex.EncodeToString(bundleHash[:]), settings.HTTPProxy, settings.HTTPSProxy, settings.NoProxy)
+	httpClientCache.Lock()
+	defer httpClientCache.Unlock()
+	if httpClient, ok := httpClientCache.clients[key]; ok {
+		return httpClient, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: !verify}
+	if verify {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if (settings.CABundleSecret != "" || settings.CABundleConfigMap != "" || len(extraCABundle) > 0) && !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, errors.New("No PEM encoded certificates found in the Keycloak CA bundle")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if settings.HTTPProxy != "" || settings.HTTPSProxy != "" || settings.NoProxy != "" {
+		proxyConfig := &httpproxy.Config{HTTPProxy: settings.HTTPProxy, HTTPSProxy: settings.HTTPSProxy, NoProxy: settings.NoProxy}
+		proxyFunc := proxyConfig.ProxyFunc()
+		proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy:               proxy,
+			TLSClientConfig:     tlsConfig,
+			TLSHandshakeTimeout: time.Second * 10,
+			IdleConnTimeout:     time.Second * 90,
+		},
+		Timeout: time.Second * 30,
+	}
+	if len(httpClientCache.clients) >= maxCachedHTTPClients {
+		httpClientCache.clients = map[string]*http.Client{}
+	}
+	httpClientCache.clients[key] = httpClient
+	return httpClient, nil
+}
+
+// NewManagedKeycloakHTTPClient : returns the HTTP client used to call an operator managed Keycloak. The client also
+// verifies Keycloak against the CA kept in caSecretName when tlsSecret holds a certificate issued by it. The OpenShift
+// route serves the router certificate instead, so the CA is not used when openShift is set
+func NewManagedKeycloakHTTPClient(c client.Client, settings HTTPClientSettings, caSecretName string, caBundleName string, tlsSecret types.NamespacedName, openShift bool) (*http.Client, error) {
+	var log = logf.Log.WithName("controller_codewind_httpClient.go")
+	var caBundle []byte
+	if !openShift {
+		ca, err := EnsureCertificateAuthority(c, caSecretName, caBundleName)
+		if err != nil {
+			log.Error(err, "Unable to load the operator CA, Keycloak TLS is not verified against it")
+		} else {
+			secret := &corev1.Secret{}
+			err = c.Get(context.TODO(), tlsSecret, secret)
+			if err == nil && CertificateIssuedBy(secret, ca) {
+				caBundle = []byte(ca.CertificatePEM)
+			}
+		}
+	}
+	return NewKeycloakHTTPClient(c, settings, caBundle)
+}
+
 // WaitForService : Wait for service to start
-func WaitForService(url string, successStatusCode int, maxRetries int) error {
+func WaitForService(httpClient HTTPClient, url string, successStatusCode int, maxRetries int) error {
+	start := time.Now()
 	retries := 0
-	client := http.Client{
-		Timeout: time.Second * 5,
-	}
 	for {
-		response, err := client.Get(url)
-		fmt.Errorf("Error: %v", err)
-		if err == nil && response.StatusCode == successStatusCode {
-			fmt.Println(".")
-			return nil
+		request, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return err
+		}
+		response, err := httpClient.Do(request)
+		if err == nil {
+			response.Body.Close()
+			if response.StatusCode == successStatusCode {
+				metrics.ObserveServiceWait(time.Since(start), true)
+				return nil
+			}
 		}
 		time.Sleep(1 * time.Second)
 		retries++
@@ -42,6 +194,6 @@ func WaitForService(url string, successStatusCode int, maxRetries int) error {
 			break
 		}
 	}
-	fmt.Println(".")
+	metrics.ObserveServiceWait(time.Since(start), false)
 	return errors.New("Service did not respond")
 }
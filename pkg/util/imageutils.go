@@ -0,0 +1,80 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"strings"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResolveImage : returns the image reference to deploy. Fields set in the override take precedence over the
+// defaults and a digest, when present, pins the image in place of the tag
+func ResolveImage(override *codewindv1alpha1.ImageSpec, defaultRepository string, defaultTag string) string {
+	repository := defaultRepository
+	tag := defaultTag
+	digest := ""
+	if override != nil {
+		if override.Repository != "" {
+			repository = override.Repository
+		}
+		if override.Tag != "" {
+			tag = override.Tag
+		}
+		digest = override.Digest
+	}
+	if digest != "" {
+		return repository + "@" + digest
+	}
+	return repository + ":" + tag
+}
+
+// ResolvePullPolicy : returns the pull policy from the CR, else the config map, else PullAlways
+func ResolvePullPolicy(specPolicy corev1.PullPolicy, configMapPolicy string) corev1.PullPolicy {
+	if specPolicy != "" {
+		return specPolicy
+	}
+	switch policy := corev1.PullPolicy(strings.TrimSpace(configMapPolicy)); policy {
+	case corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		return policy
+	}
+	return corev1.PullAlways
+}
+
+// ResolvePullSecrets : combines the comma separated secret names from the config map with the secrets listed in the CR
+func ResolvePullSecrets(specSecrets []corev1.LocalObjectReference, configMapSecrets string) []corev1.LocalObjectReference {
+	var pullSecrets []corev1.LocalObjectReference
+	seen := map[string]bool{}
+	for _, name := range strings.Split(configMapSecrets, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+	for _, secret := range specSecrets {
+		if secret.Name != "" && !seen[secret.Name] {
+			seen[secret.Name] = true
+			pullSecrets = append(pullSecrets, secret)
+		}
+	}
+	return pullSecrets
+}
+
+// ValueOrDefault : returns the value when it is set, else the default
+func ValueOrDefault(value string, defaultValue string) string {
+	if strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	return defaultValue
+}
@@ -12,52 +12,23 @@
 package util
 
 import (
+	"context"
 	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/discovery"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
-// DetectOpenShift determines if we're running on an OpenShift cluster
-func DetectOpenShift() (isOpenshift bool, isOpenshift4 bool, anError error) {
-	apiGroups, err := getAPIList()
-	if err != nil {
-		return false, false, err
-	}
-	for _, apiGroup := range apiGroups {
-		if apiGroup.Name == "route.openshift.io" {
-			isOpenshift = true
-		}
-		if apiGroup.Name == "config.openshift.io" {
-			isOpenshift4 = true
-		}
-	}
-	return
-}
-
-func getAPIList() ([]v1.APIGroup, error) {
-	discoveryClient, err := getDiscoveryClient()
-	if err != nil {
-		return nil, err
-	}
-	apiList, err := discoveryClient.ServerGroups()
-	if err != nil {
-		return nil, err
-	}
-	return apiList.Groups, nil
-}
-
-func getDiscoveryClient() (*discovery.DiscoveryClient, error) {
-	kubeconfig, err := config.GetConfig()
-	if err != nil {
-		return nil, err
-	}
-	return discovery.NewDiscoveryClientForConfig(kubeconfig)
-}
-
 // CreateTimestamp : Create a timestamp
 func CreateTimestamp() int64 {
 	return time.Now().UnixNano() / int64(time.Millisecond)
@@ -82,3 +53,36 @@ func GenerateRandomString(length int) string {
 	}
 	return string(bytes)
 }
+
+// GenerateInstanceID : Generates the short lower case ID that names the resources of a Codewind or Keycloak instance
+func GenerateInstanceID() string {
+	return strings.ToLower(strconv.FormatInt(CreateTimestamp(), 36) + GenerateRandomString(4))
+}
+
+// ReadConfigMapData : returns the data of a config map, or an empty map when the config map does not exist
+func ReadConfigMapData(c client.Client, name string, namespace string) (map[string]string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, configMap)
+	if err != nil && k8serr.IsNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if configMap.Data == nil {
+		return map[string]string{}, nil
+	}
+	return configMap.Data, nil
+}
+
+// ObjectKindAndName : returns the kind and name of an object, for use in events and log messages
+func ObjectKindAndName(scheme *runtime.Scheme, object runtime.Object) (string, string) {
+	name := ""
+	if accessor, err := meta.Accessor(object); err == nil {
+		name = accessor.GetName()
+	}
+	gvk, err := apiutil.GVKForObject(object, scheme)
+	if err != nil {
+		return reflect.Indirect(reflect.ValueOf(object)).Type().Name(), name
+	}
+	return gvk.Kind, name
+}
@@ -0,0 +1,240 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// The Merge functions below copy the fields the operator owns from a freshly built (desired) object
+// into the object read back from the cluster (existing). Fields the builders leave unset, such as
+// values defaulted by the API server or added by other controllers, are left untouched.
+// Each function returns true when the existing object was modified and needs to be updated.
+
+// MergeDeployment : merges the operator owned fields of a desired Deployment into an existing one
+func MergeDeployment(existing *appsv1.Deployment, desired *appsv1.Deployment) bool {
+	changed := mergeStringMap(&existing.Labels, desired.Labels)
+	if desired.Spec.Replicas != nil && (existing.Spec.Replicas == nil || *existing.Spec.Replicas != *desired.Spec.Replicas) {
+		replicas := *desired.Spec.Replicas
+		existing.Spec.Replicas = &replicas
+		changed = true
+	}
+	// Only the type is compared as the API server defaults the rolling update parameters
+	if desired.Spec.Strategy.Type != "" && existing.Spec.Strategy.Type != desired.Spec.Strategy.Type {
+		existing.Spec.Strategy = desired.Spec.Strategy
+		changed = true
+	}
+	if mergeStringMap(&existing.Spec.Template.Labels, desired.Spec.Template.Labels) {
+		changed = true
+	}
+	if mergeStringMap(&existing.Spec.Template.Annotations, desired.Spec.Template.Annotations) {
+		changed = true
+	}
+	if MergePodSpec(&existing.Spec.Template.Spec, &desired.Spec.Template.Spec) {
+		changed = true
+	}
+	return changed
+}
+
+// MergePodSpec : merges the operator owned fields of a desired PodSpec into an existing one
+func MergePodSpec(existing *corev1.PodSpec, desired *corev1.PodSpec) bool {
+	changed := false
+	if desired.ServiceAccountName != "" && existing.ServiceAccountName != desired.ServiceAccountName {
+		existing.ServiceAccountName = desired.ServiceAccountName
+		changed = true
+	}
+	if len(existing.ImagePullSecrets) != len(desired.ImagePullSecrets) || !equality.Semantic.DeepDerivative(desired.ImagePullSecrets, existing.ImagePullSecrets) {
+		existing.ImagePullSecrets = desired.ImagePullSecrets
+		changed = true
+	}
+	if len(existing.Volumes) != len(desired.Volumes) || !equality.Semantic.DeepDerivative(desired.Volumes, existing.Volumes) {
+		existing.Volumes = desired.Volumes
+		changed = true
+	}
+	if !equality.Semantic.DeepDerivative(desired.SecurityContext, existing.SecurityContext) {
+		existing.SecurityContext = desired.SecurityContext
+		changed = true
+	}
+	// The scheduling fields are compared exactly so that settings removed from the CR are also removed from the pod
+	if !equality.Semantic.DeepEqual(desired.NodeSelector, existing.NodeSelector) {
+		existing.NodeSelector = desired.NodeSelector
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(desired.Tolerations, existing.Tolerations) {
+		existing.Tolerations = desired.Tolerations
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(desired.Affinity, existing.Affinity) {
+		existing.Affinity = desired.Affinity
+		changed = true
+	}
+	if len(existing.InitContainers) != len(desired.InitContainers) || !equality.Semantic.DeepDerivative(desired.InitContainers, existing.InitContainers) {
+		existing.InitContainers = desired.InitContainers
+		changed = true
+	}
+	for _, desiredContainer := range desired.Containers {
+		found := false
+		for i := range existing.Containers {
+			if existing.Containers[i].Name == desiredContainer.Name {
+				found = true
+				if mergeContainer(&existing.Containers[i], &desiredContainer) {
+					changed = true
+				}
+				break
+			}
+		}
+		if !found {
+			existing.Containers = append(existing.Containers, desiredContainer)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// mergeContainer : merges the operator owned fields of a desired container into an existing one
+func mergeContainer(existing *corev1.Container, desired *corev1.Container) bool {
+	changed := false
+	if existing.Image != desired.Image {
+		existing.Image = desired.Image
+		changed = true
+	}
+	if desired.ImagePullPolicy != "" && existing.ImagePullPolicy != desired.ImagePullPolicy {
+		existing.ImagePullPolicy = desired.ImagePullPolicy
+		changed = true
+	}
+	if len(existing.Env) != len(desired.Env) || !equality.Semantic.DeepDerivative(desired.Env, existing.Env) {
+		existing.Env = desired.Env
+		changed = true
+	}
+	if len(existing.Ports) != len(desired.Ports) || !equality.Semantic.DeepDerivative(desired.Ports, existing.Ports) {
+		existing.Ports = desired.Ports
+		changed = true
+	}
+	if len(existing.VolumeMounts) != len(desired.VolumeMounts) || !equality.Semantic.DeepDerivative(desired.VolumeMounts, existing.VolumeMounts) {
+		existing.VolumeMounts = desired.VolumeMounts
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(desired.Resources, existing.Resources) {
+		existing.Resources = desired.Resources
+		changed = true
+	}
+	if !equality.Semantic.DeepDerivative(desired.SecurityContext, existing.SecurityContext) {
+		existing.SecurityContext = desired.SecurityContext
+		changed = true
+	}
+	if (desired.ReadinessProbe == nil) != (existing.ReadinessProbe == nil) || !equality.Semantic.DeepDerivative(desired.ReadinessProbe, existing.ReadinessProbe) {
+		existing.ReadinessProbe = desired.ReadinessProbe
+		changed = true
+	}
+	return changed
+}
+
+// MergeService : merges the operator owned fields of a desired Service into an existing one
+func MergeService(existing *corev1.Service, desired *corev1.Service) bool {
+	changed := mergeStringMap(&existing.Labels, desired.Labels)
+	if len(existing.Spec.Selector) != len(desired.Spec.Selector) || !equality.Semantic.DeepDerivative(desired.Spec.Selector, existing.Spec.Selector) {
+		existing.Spec.Selector = desired.Spec.Selector
+		changed = true
+	}
+	// The API server defaults an unset target port to the service port, do the same before comparing
+	desiredPorts := make([]corev1.ServicePort, len(desired.Spec.Ports))
+	for i, port := range desired.Spec.Ports {
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+		desiredPorts[i] = port
+	}
+	if len(existing.Spec.Ports) != len(desiredPorts) || !equality.Semantic.DeepDerivative(desiredPorts, existing.Spec.Ports) {
+		existing.Spec.Ports = desiredPorts
+		changed = true
+	}
+	return changed
+}
+
+// MergeIngress : merges the operator owned fields of a desired Ingress into an existing one
+func MergeIngress(existing *extv1beta1.Ingress, desired *extv1beta1.Ingress) bool {
+	changed := mergeStringMap(&existing.Labels, desired.Labels)
+	if mergeStringMap(&existing.Annotations, desired.Annotations) {
+		changed = true
+	}
+	if len(existing.Spec.TLS) != len(desired.Spec.TLS) || !equality.Semantic.DeepDerivative(desired.Spec.TLS, existing.Spec.TLS) {
+		existing.Spec.TLS = desired.Spec.TLS
+		changed = true
+	}
+	if len(existing.Spec.Rules) != len(desired.Spec.Rules) || !equality.Semantic.DeepDerivative(desired.Spec.Rules, existing.Spec.Rules) {
+		existing.Spec.Rules = desired.Spec.Rules
+		changed = true
+	}
+	return changed
+}
+
+// MergeRoute : merges the operator owned fields of a desired OpenShift Route into an existing one
+func MergeRoute(existing *routev1.Route, desired *routev1.Route) bool {
+	changed := mergeStringMap(&existing.Labels, desired.Labels)
+	if mergeStringMap(&existing.Annotations, desired.Annotations) {
+		changed = true
+	}
+	if desired.Spec.Host != "" && existing.Spec.Host != desired.Spec.Host {
+		existing.Spec.Host = desired.Spec.Host
+		changed = true
+	}
+	if !equality.Semantic.DeepDerivative(desired.Spec.Port, existing.Spec.Port) {
+		existing.Spec.Port = desired.Spec.Port
+		changed = true
+	}
+	if !equality.Semantic.DeepDerivative(desired.Spec.TLS, existing.Spec.TLS) {
+		existing.Spec.TLS = desired.Spec.TLS
+		changed = true
+	}
+	if !equality.Semantic.DeepDerivative(desired.Spec.To, existing.Spec.To) {
+		existing.Spec.To = desired.Spec.To
+		changed = true
+	}
+	return changed
+}
+
+// MergeCertificate : merges the operator owned fields of a desired cert-manager Certificate into an existing one
+func MergeCertificate(existing *unstructured.Unstructured, desired *unstructured.Unstructured) bool {
+	labels := existing.GetLabels()
+	changed := mergeStringMap(&labels, desired.GetLabels())
+	if changed {
+		existing.SetLabels(labels)
+	}
+	desiredSpec, _, _ := unstructured.NestedMap(desired.Object, "spec")
+	existingSpec, _, _ := unstructured.NestedMap(existing.Object, "spec")
+	if !equality.Semantic.DeepDerivative(desiredSpec, existingSpec) {
+		existing.Object["spec"] = desiredSpec
+		changed = true
+	}
+	return changed
+}
+
+// mergeStringMap : copies every desired key into the existing map, keeping keys added by others
+func mergeStringMap(existing *map[string]string, desired map[string]string) bool {
+	changed := false
+	for key, value := range desired {
+		if *existing == nil {
+			*existing = make(map[string]string)
+		}
+		if current, ok := (*existing)[key]; !ok || current != value {
+			(*existing)[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,423 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// desiredDeployment : returns a deployment as the builders create it
+func desiredDeployment() *appsv1.Deployment {
+	replicas := int32(1)
+	labels := map[string]string{"app": "codewind-pfe", "codewindWorkspace": "k8x1y2z3"}
+	dep := &appsv1.Deployment{}
+	dep.Name = "codewind-pfe-k8x1y2z3"
+	dep.Labels = labels
+	dep.Spec.Replicas = &replicas
+	dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+	dep.Spec.Template.Labels = labels
+	dep.Spec.Template.Spec = corev1.PodSpec{
+		ServiceAccountName: "codewind-k8x1y2z3",
+		Volumes: []corev1.Volume{{
+			Name: "tls-certs",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "secret-codewind-tls-k8x1y2z3"},
+			},
+		}},
+		Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "codewind", Effect: corev1.TaintEffectNoSchedule}},
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}},
+					}},
+				},
+			},
+		},
+		Containers: []corev1.Container{{
+			Name:  "codewind-pfe",
+			Image: "eclipse/codewind-pfe-amd64:latest",
+			Env: []corev1.EnvVar{
+				{Name: "LOG_LEVEL", Value: "info"},
+				{Name: "WORKSPACE_ID", Value: "k8x1y2z3"},
+			},
+			Ports: []corev1.ContainerPort{{ContainerPort: 9191}},
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      "tls-certs",
+				MountPath: "/tlscerts",
+				ReadOnly:  true,
+			}},
+		}},
+	}
+	return dep
+}
+
+// stored : returns a copy of a built deployment with the fields the API server defaults filled in
+func stored(desired *appsv1.Deployment) *appsv1.Deployment {
+	dep := desired.DeepCopy()
+	revisionHistoryLimit := int32(10)
+	progressDeadlineSeconds := int32(600)
+	maxSurge := intstr.FromString("25%")
+	dep.Spec.RevisionHistoryLimit = &revisionHistoryLimit
+	dep.Spec.ProgressDeadlineSeconds = &progressDeadlineSeconds
+	if dep.Spec.Strategy.Type == appsv1.RollingUpdateDeploymentStrategyType {
+		dep.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge, MaxUnavailable: &maxSurge}
+	}
+	podSpec := &dep.Spec.Template.Spec
+	podSpec.RestartPolicy = corev1.RestartPolicyAlways
+	podSpec.DNSPolicy = corev1.DNSClusterFirst
+	podSpec.SchedulerName = corev1.DefaultSchedulerName
+	podSpec.SecurityContext = &corev1.PodSecurityContext{}
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Secret != nil {
+			defaultMode := int32(0644)
+			podSpec.Volumes[i].Secret.DefaultMode = &defaultMode
+		}
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+		container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+		container.ImagePullPolicy = corev1.PullAlways
+		for j := range container.Ports {
+			container.Ports[j].Protocol = corev1.ProtocolTCP
+		}
+	}
+	return dep
+}
+
+func TestMergeDeployment(t *testing.T) {
+	tests := []struct {
+		name        string
+		edit        func(existing *appsv1.Deployment, desired *appsv1.Deployment)
+		wantChanged bool
+		check       func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment)
+	}{
+		{
+			name:        "defaulted fields are not drift",
+			edit:        func(existing *appsv1.Deployment, desired *appsv1.Deployment) {},
+			wantChanged: false,
+		},
+		{
+			name: "labels added by others are kept",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				existing.Labels = map[string]string{"app": "codewind-pfe", "codewindWorkspace": "k8x1y2z3", "team": "tools"}
+				existing.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2020-05-01T10:00:00Z"}
+			},
+			wantChanged: false,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if existing.Labels["team"] != "tools" {
+					t.Errorf("label team = %q, want tools", existing.Labels["team"])
+				}
+				if existing.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] == "" {
+					t.Errorf("pod template annotation kubectl.kubernetes.io/restartedAt was removed")
+				}
+			},
+		},
+		{
+			name: "changed label is restored",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				existing.Labels = map[string]string{"app": "other", "codewindWorkspace": "k8x1y2z3"}
+			},
+			wantChanged: true,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if existing.Labels["app"] != "codewind-pfe" {
+					t.Errorf("label app = %q, want codewind-pfe", existing.Labels["app"])
+				}
+			},
+		},
+		{
+			name: "removed env var is dropped",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				desired.Spec.Template.Spec.Containers[0].Env = desired.Spec.Template.Spec.Containers[0].Env[:1]
+			},
+			wantChanged: true,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if !equality.Semantic.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env, desired.Spec.Template.Spec.Containers[0].Env) {
+					t.Errorf("env = %v, want %v", existing.Spec.Template.Spec.Containers[0].Env, desired.Spec.Template.Spec.Containers[0].Env)
+				}
+			},
+		},
+		{
+			name: "removed tolerations are dropped",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				desired.Spec.Template.Spec.Tolerations = nil
+			},
+			wantChanged: true,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if len(existing.Spec.Template.Spec.Tolerations) != 0 {
+					t.Errorf("tolerations = %v, want none", existing.Spec.Template.Spec.Tolerations)
+				}
+			},
+		},
+		{
+			name: "removed affinity is dropped",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				desired.Spec.Template.Spec.Affinity = nil
+			},
+			wantChanged: true,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if existing.Spec.Template.Spec.Affinity != nil {
+					t.Errorf("affinity = %v, want none", existing.Spec.Template.Spec.Affinity)
+				}
+			},
+		},
+		{
+			name: "removed readiness probe is dropped",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				existing.Spec.Template.Spec.Containers[0].ReadinessProbe = &corev1.Probe{PeriodSeconds: 10}
+			},
+			wantChanged: true,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if existing.Spec.Template.Spec.Containers[0].ReadinessProbe != nil {
+					t.Errorf("readiness probe = %v, want none", existing.Spec.Template.Spec.Containers[0].ReadinessProbe)
+				}
+			},
+		},
+		{
+			name: "changed replicas are restored",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				replicas := int32(0)
+				desired.Spec.Replicas = &replicas
+			},
+			wantChanged: true,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if *existing.Spec.Replicas != 0 {
+					t.Errorf("replicas = %d, want 0", *existing.Spec.Replicas)
+				}
+			},
+		},
+		{
+			name: "changed strategy type replaces the strategy",
+			edit: func(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				desired.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+			},
+			wantChanged: true,
+			check: func(t *testing.T, existing *appsv1.Deployment, desired *appsv1.Deployment) {
+				if existing.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType || existing.Spec.Strategy.RollingUpdate != nil {
+					t.Errorf("strategy = %v, want Recreate without rolling update parameters", existing.Spec.Strategy)
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			desired := desiredDeployment()
+			existing := stored(desired)
+			test.edit(existing, desired)
+			changed := MergeDeployment(existing, desired)
+			if changed != test.wantChanged {
+				t.Errorf("MergeDeployment() = %v, want %v", changed, test.wantChanged)
+			}
+			if test.check != nil {
+				test.check(t, existing, desired)
+			}
+			if MergeDeployment(existing, desired) {
+				t.Errorf("MergeDeployment() reported a change after merging")
+			}
+		})
+	}
+}
+
+func TestMergeService(t *testing.T) {
+	desiredService := func() *corev1.Service {
+		service := &corev1.Service{}
+		service.Labels = map[string]string{"app": "codewind-gatekeeper"}
+		service.Spec.Selector = map[string]string{"app": "codewind-gatekeeper"}
+		service.Spec.Ports = []corev1.ServicePort{{Name: "codewind-gatekeeper-http", Port: 9096}}
+		return service
+	}
+	tests := []struct {
+		name        string
+		edit        func(existing *corev1.Service, desired *corev1.Service)
+		wantChanged bool
+	}{
+		{
+			name:        "defaulted fields are not drift",
+			edit:        func(existing *corev1.Service, desired *corev1.Service) {},
+			wantChanged: false,
+		},
+		{
+			name: "changed target port is restored",
+			edit: func(existing *corev1.Service, desired *corev1.Service) {
+				existing.Spec.Ports[0].TargetPort = intstr.FromInt(8080)
+			},
+			wantChanged: true,
+		},
+		{
+			name: "extra selector is dropped",
+			edit: func(existing *corev1.Service, desired *corev1.Service) {
+				existing.Spec.Selector["tier"] = "web"
+			},
+			wantChanged: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			desired := desiredService()
+			existing := desiredService()
+			existing.Labels["team"] = "tools"
+			existing.Spec.ClusterIP = "10.0.0.1"
+			existing.Spec.Type = corev1.ServiceTypeClusterIP
+			existing.Spec.SessionAffinity = corev1.ServiceAffinityNone
+			existing.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+			existing.Spec.Ports[0].TargetPort = intstr.FromInt(9096)
+			test.edit(existing, desired)
+			changed := MergeService(existing, desired)
+			if changed != test.wantChanged {
+				t.Errorf("MergeService() = %v, want %v", changed, test.wantChanged)
+			}
+			if existing.Spec.ClusterIP != "10.0.0.1" {
+				t.Errorf("cluster IP = %q, want 10.0.0.1", existing.Spec.ClusterIP)
+			}
+			if existing.Labels["team"] != "tools" {
+				t.Errorf("label team = %q, want tools", existing.Labels["team"])
+			}
+			if MergeService(existing, desired) {
+				t.Errorf("MergeService() reported a change after merging")
+			}
+		})
+	}
+}
+
+func TestMergeRoute(t *testing.T) {
+	desiredRoute := func() *routev1.Route {
+		weight := int32(100)
+		route := &routev1.Route{}
+		route.Labels = map[string]string{"app": "codewind-gatekeeper"}
+		route.Annotations = map[string]string{"codewind.eclipse.org/state": "running"}
+		route.Spec.Host = "codewind-gatekeeper-k8x1y2z3.codewind.apps.example.com"
+		route.Spec.Port = &routev1.RoutePort{TargetPort: intstr.FromInt(9096)}
+		route.Spec.TLS = &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationPassthrough,
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		}
+		route.Spec.To = routev1.RouteTargetReference{Kind: "Service", Name: "codewind-gatekeeper-k8x1y2z3", Weight: &weight}
+		return route
+	}
+	tests := []struct {
+		name        string
+		edit        func(existing *routev1.Route, desired *routev1.Route)
+		wantChanged bool
+	}{
+		{
+			name:        "defaulted fields are not drift",
+			edit:        func(existing *routev1.Route, desired *routev1.Route) {},
+			wantChanged: false,
+		},
+		{
+			name: "defaulted weight is not drift",
+			edit: func(existing *routev1.Route, desired *routev1.Route) {
+				desired.Spec.To.Weight = nil
+			},
+			wantChanged: false,
+		},
+		{
+			name: "annotations added by others are kept",
+			edit: func(existing *routev1.Route, desired *routev1.Route) {
+				existing.Annotations["openshift.io/host.generated"] = "false"
+			},
+			wantChanged: false,
+		},
+		{
+			name: "changed state annotation is restored",
+			edit: func(existing *routev1.Route, desired *routev1.Route) {
+				desired.Annotations["codewind.eclipse.org/state"] = "asleep"
+			},
+			wantChanged: true,
+		},
+		{
+			name: "edge termination certificate is dropped for passthrough",
+			edit: func(existing *routev1.Route, desired *routev1.Route) {
+				existing.Spec.TLS = &routev1.TLSConfig{
+					Termination:                   routev1.TLSTerminationEdge,
+					InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+					Certificate:                   "certificate",
+					Key:                           "key",
+				}
+			},
+			wantChanged: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			desired := desiredRoute()
+			existing := desiredRoute()
+			existing.Spec.WildcardPolicy = routev1.WildcardPolicyNone
+			test.edit(existing, desired)
+			changed := MergeRoute(existing, desired)
+			if changed != test.wantChanged {
+				t.Errorf("MergeRoute() = %v, want %v", changed, test.wantChanged)
+			}
+			if existing.Spec.WildcardPolicy != routev1.WildcardPolicyNone {
+				t.Errorf("wildcard policy = %q, want None", existing.Spec.WildcardPolicy)
+			}
+			if existing.Spec.TLS.Termination == routev1.TLSTerminationPassthrough && existing.Spec.TLS.Key != "" {
+				t.Errorf("passthrough route kept the edge termination key")
+			}
+			if MergeRoute(existing, desired) {
+				t.Errorf("MergeRoute() reported a change after merging")
+			}
+		})
+	}
+}
+
+func TestMergeIngress(t *testing.T) {
+	desiredIngress := func(serviceName string) *extv1beta1.Ingress {
+		ingress := &extv1beta1.Ingress{}
+		ingress.Labels = map[string]string{"app": "codewind-gatekeeper"}
+		ingress.Annotations = map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"}
+		ingress.Spec.Rules = []extv1beta1.IngressRule{{
+			Host: "codewind-gatekeeper-k8x1y2z3.codewind.example.com",
+			IngressRuleValue: extv1beta1.IngressRuleValue{
+				HTTP: &extv1beta1.HTTPIngressRuleValue{
+					Paths: []extv1beta1.HTTPIngressPath{{
+						Path:    "/",
+						Backend: extv1beta1.IngressBackend{ServiceName: serviceName, ServicePort: intstr.FromInt(9096)},
+					}},
+				},
+			},
+		}}
+		return ingress
+	}
+	tests := []struct {
+		name        string
+		existing    *extv1beta1.Ingress
+		desired     *extv1beta1.Ingress
+		wantChanged bool
+	}{
+		{"unchanged ingress", desiredIngress("codewind-gatekeeper-k8x1y2z3"), desiredIngress("codewind-gatekeeper-k8x1y2z3"), false},
+		{"changed backend is restored", desiredIngress("codewind-asleep-k8x1y2z3"), desiredIngress("codewind-gatekeeper-k8x1y2z3"), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.existing.Annotations["kubernetes.io/ingress.class"] = "nginx"
+			changed := MergeIngress(test.existing, test.desired)
+			if changed != test.wantChanged {
+				t.Errorf("MergeIngress() = %v, want %v", changed, test.wantChanged)
+			}
+			if test.existing.Annotations["kubernetes.io/ingress.class"] != "nginx" {
+				t.Errorf("annotation kubernetes.io/ingress.class was removed")
+			}
+			if !equality.Semantic.DeepEqual(test.existing.Spec.Rules, test.desired.Spec.Rules) {
+				t.Errorf("rules = %v, want %v", test.existing.Spec.Rules, test.desired.Spec.Rules)
+			}
+		})
+	}
+}
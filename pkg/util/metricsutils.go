@@ -0,0 +1,88 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// metricsClient : client used to scrape metrics endpoints. The gatekeeper certificate names its ingress host, not the
+// service address that is scraped, so it is not verified
+var metricsClient = &http.Client{
+	Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		IdleConnTimeout: time.Second * 90,
+	},
+	Timeout: time.Second * 10,
+}
+
+// ScrapeMetricTotal : reads a Prometheus text endpoint and returns the sum of every sample of the named metric.
+// Counters and gauges contribute their value, summaries and histograms contribute their sample count. Samples with a
+// label set to excludedPath are skipped, so that the requests of the scrape itself are not counted
+func ScrapeMetricTotal(url string, metricName string, excludedPath string) (float64, error) {
+	response, err := metricsClient.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("metrics endpoint %v returned status %v", url, response.StatusCode)
+	}
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(response.Body)
+	if err != nil {
+		return 0, err
+	}
+	family, ok := families[metricName]
+	if !ok {
+		return 0, fmt.Errorf("metric %v not found at %v", metricName, url)
+	}
+	total := float64(0)
+	for _, metric := range family.GetMetric() {
+		if hasLabelValue(metric, excludedPath) {
+			continue
+		}
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			total += metric.GetCounter().GetValue()
+		case dto.MetricType_GAUGE:
+			total += metric.GetGauge().GetValue()
+		case dto.MetricType_SUMMARY:
+			total += float64(metric.GetSummary().GetSampleCount())
+		case dto.MetricType_HISTOGRAM:
+			total += float64(metric.GetHistogram().GetSampleCount())
+		default:
+			total += metric.GetUntyped().GetValue()
+		}
+	}
+	return total, nil
+}
+
+// hasLabelValue : true when any label of the sample is set to value
+func hasLabelValue(metric *dto.Metric, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, label := range metric.GetLabel() {
+		if label.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"strings"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ResolvePodTemplate : combines the pod settings from the CR with the YAML defaults held in the operator config map.
+// Each field set in the CR replaces the matching default. The CR settings are still returned when the defaults
+// cannot be parsed
+func ResolvePodTemplate(override *codewindv1alpha1.PodTemplateOverrides, configMapDefaults string) (*codewindv1alpha1.PodTemplateOverrides, error) {
+	resolved := &codewindv1alpha1.PodTemplateOverrides{}
+	var parseErr error
+	if strings.TrimSpace(configMapDefaults) != "" {
+		parseErr = yaml.UnmarshalStrict([]byte(configMapDefaults), resolved)
+		if parseErr != nil {
+			resolved = &codewindv1alpha1.PodTemplateOverrides{}
+		}
+	}
+	if override != nil {
+		if override.Resources != nil {
+			resolved.Resources = override.Resources
+		}
+		if override.NodeSelector != nil {
+			resolved.NodeSelector = override.NodeSelector
+		}
+		if override.Tolerations != nil {
+			resolved.Tolerations = override.Tolerations
+		}
+		if override.Affinity != nil {
+			resolved.Affinity = override.Affinity
+		}
+	}
+	return resolved, parseErr
+}
+
+// SelectPod : picks the pod best able to serve requests from a set of replicas, preferring ready pods, then running
+// pods, and skipping pods that are being deleted where possible
+func SelectPod(pods []corev1.Pod) *corev1.Pod {
+	var running *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return pod
+			}
+		}
+		if running == nil {
+			running = pod
+		}
+	}
+	if running != nil {
+		return running
+	}
+	if len(pods) > 0 {
+		return &pods[0]
+	}
+	return nil
+}
+
+// ApplyPodTemplate : merges the resource and scheduling settings resolved for a pod into the pod spec built for it. The
+// resources apply to every container, and the scheduling fields replace those of the pod
+func ApplyPodTemplate(podSpec *corev1.PodSpec, template *codewindv1alpha1.PodTemplateOverrides) {
+	if template == nil {
+		return
+	}
+	if template.Resources != nil {
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].Resources = *template.Resources.DeepCopy()
+		}
+	}
+	podSpec.NodeSelector = template.NodeSelector
+	podSpec.Tolerations = template.Tolerations
+	podSpec.Affinity = template.Affinity
+}
@@ -0,0 +1,47 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GeneratePassword : Generates a random password from a cryptographic source. Letters and digits only, so that the
+// password can be sent in form encoded requests without escaping
+func GeneratePassword(length int) (string, error) {
+	var options = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+	password := make([]rune, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(options))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = options[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// CredentialRotationDue : true when a rotation has been requested, the requested time has been reached and no
+// rotation has happened since. Otherwise returns how long to wait for a requested rotation, zero when none is pending
+func CredentialRotationDue(requested *metav1.Time, rotated *metav1.Time, now time.Time) (bool, time.Duration) {
+	if requested == nil || (rotated != nil && !rotated.Before(requested)) {
+		return false, 0
+	}
+	if now.Before(requested.Time) {
+		return false, requested.Sub(now)
+	}
+	return true, 0
+}
@@ -0,0 +1,206 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PVCStorageClassName : storage class requested by the CR, else the storage class picked by the operator
+func PVCStorageClassName(storage *codewindv1alpha1.StorageSpec, defaultStorageClassName string) string {
+	if storage != nil && storage.StorageClassName != "" {
+		return storage.StorageClassName
+	}
+	return defaultStorageClassName
+}
+
+// PVCAccessModes : access modes requested by the CR, else the default access mode of the PVC
+func PVCAccessModes(storage *codewindv1alpha1.StorageSpec, defaultAccessMode corev1.PersistentVolumeAccessMode) []corev1.PersistentVolumeAccessMode {
+	if storage != nil && len(storage.AccessModes) > 0 {
+		return storage.AccessModes
+	}
+	return []corev1.PersistentVolumeAccessMode{defaultAccessMode}
+}
+
+// NewPVCStorageStatus : storage status of a PVC the operator has just created, which is waiting to be bound
+func NewPVCStorageStatus(pvc *corev1.PersistentVolumeClaim) *codewindv1alpha1.StorageStatus {
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	status := &codewindv1alpha1.StorageStatus{
+		AccessModes:   pvc.Spec.AccessModes,
+		RequestedSize: requested.String(),
+		Message:       "Waiting for PVC " + pvc.Name + " to be bound",
+	}
+	if pvc.Spec.StorageClassName != nil {
+		status.StorageClassName = *pvc.Spec.StorageClassName
+	}
+	return status
+}
+
+// PVCDeploymentStrategy : rolling updates when every node can mount the PVC read-write, else the old pod is stopped
+// before the new one starts so that it can attach the PVC
+func PVCDeploymentStrategy(accessModes []corev1.PersistentVolumeAccessMode) appsv1.DeploymentStrategy {
+	for _, accessMode := range accessModes {
+		if accessMode == corev1.ReadWriteMany {
+			return appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+		}
+	}
+	return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+}
+
+// ExpandPVC : raises the storage request of a bound PVC to size when size is larger and the storage class of the
+// PVC allows volume expansion. Returns the observed storage status and true when the PVC was updated
+func ExpandPVC(c client.Client, pvc *corev1.PersistentVolumeClaim, size string) (*codewindv1alpha1.StorageStatus, bool, error) {
+	requested, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, false, err
+	}
+	status := &codewindv1alpha1.StorageStatus{
+		AccessModes:   pvc.Spec.AccessModes,
+		RequestedSize: requested.String(),
+	}
+	if pvc.Spec.StorageClassName != nil {
+		status.StorageClassName = *pvc.Spec.StorageClassName
+	}
+	capacity, hasCapacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if hasCapacity {
+		status.Capacity = capacity.String()
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		status.Message = "Waiting for PVC " + pvc.Name + " to be bound"
+		return status, false, nil
+	}
+
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch requested.Cmp(current) {
+	case -1:
+		status.ResizeState = codewindv1alpha1.StorageResizeStateUnsupported
+		status.Message = fmt.Sprintf("PVC %s cannot shrink from %s to %s", pvc.Name, current.String(), requested.String())
+		return status, false, nil
+	case 1:
+		allowed, err := volumeExpansionAllowed(c, status.StorageClassName)
+		if err != nil {
+			return status, false, err
+		}
+		if !allowed {
+			status.ResizeState = codewindv1alpha1.StorageResizeStateUnsupported
+			status.Message = fmt.Sprintf("Storage class %q of PVC %s does not allow volume expansion", status.StorageClassName, pvc.Name)
+			return status, false, nil
+		}
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+		err = c.Update(context.TODO(), pvc)
+		if err != nil {
+			status.ResizeState = codewindv1alpha1.StorageResizeStateFailed
+			status.Message = fmt.Sprintf("Unable to expand PVC %s to %s: %v", pvc.Name, requested.String(), err)
+			return status, false, err
+		}
+		status.ResizeState = codewindv1alpha1.StorageResizeStateResizing
+		status.Message = fmt.Sprintf("Expanding PVC %s from %s to %s", pvc.Name, current.String(), requested.String())
+		return status, true, nil
+	}
+
+	// The request already matches, report the progress of the storage provider
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimResizing:
+			status.ResizeState = codewindv1alpha1.StorageResizeStateResizing
+			status.Message = ValueOrDefault(condition.Message, "Expanding the volume of PVC "+pvc.Name)
+			return status, false, nil
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			status.ResizeState = codewindv1alpha1.StorageResizeStateFileSystemResizePending
+			status.Message = ValueOrDefault(condition.Message, "Waiting for a pod to mount PVC "+pvc.Name+" to resize its file system")
+			return status, false, nil
+		}
+	}
+	if hasCapacity && capacity.Cmp(requested) < 0 {
+		status.ResizeState = codewindv1alpha1.StorageResizeStateResizing
+		status.Message = "Waiting for the volume of PVC " + pvc.Name + " to reach " + requested.String()
+		return status, false, nil
+	}
+	status.ResizeState = codewindv1alpha1.StorageResizeStateComplete
+	return status, false, nil
+}
+
+// volumeExpansionAllowed : true when the storage class exists and allows volume expansion
+func volumeExpansionAllowed(c client.Client, storageClassName string) (bool, error) {
+	if storageClassName == "" {
+		return false, nil
+	}
+	storageClass := &storagev1.StorageClass{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, storageClass)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+// ValidateStorageSpec : error when an access mode requested by the CR is not a PVC access mode
+func ValidateStorageSpec(storage *codewindv1alpha1.StorageSpec) error {
+	if storage == nil {
+		return nil
+	}
+	for _, accessMode := range storage.AccessModes {
+		switch accessMode {
+		case corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany:
+		default:
+			return fmt.Errorf("spec.storage.accessModes '%s' is not one of ReadWriteOnce, ReadOnlyMany or ReadWriteMany", accessMode)
+		}
+	}
+	return nil
+}
+
+// ValidateStorageUpdate : error when an update shrinks the storage size, or changes the storage class or access
+// modes, which are fixed once the PVC is created
+func ValidateStorageUpdate(oldStorage *codewindv1alpha1.StorageSpec, storage *codewindv1alpha1.StorageSpec, oldStorageSize string, storageSize string) error {
+	if oldStorageSize != "" && storageSize != "" {
+		oldSize, err := resource.ParseQuantity(oldStorageSize)
+		if err == nil {
+			size, err := resource.ParseQuantity(storageSize)
+			if err == nil && size.Cmp(oldSize) < 0 {
+				return fmt.Errorf("spec.storageSize cannot shrink from %s to %s, PVCs can only be expanded", oldStorageSize, storageSize)
+			}
+		}
+	}
+	if oldStorage == nil {
+		oldStorage = &codewindv1alpha1.StorageSpec{}
+	}
+	if storage == nil {
+		storage = &codewindv1alpha1.StorageSpec{}
+	}
+	if storage.StorageClassName != oldStorage.StorageClassName {
+		return errors.New("spec.storage.storageClassName cannot be changed after the PVC is created")
+	}
+	if !reflect.DeepEqual(storage.AccessModes, oldStorage.AccessModes) && (len(storage.AccessModes) > 0 || len(oldStorage.AccessModes) > 0) {
+		return errors.New("spec.storage.accessModes cannot be changed after the PVC is created")
+	}
+	return nil
+}
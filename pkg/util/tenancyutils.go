@@ -0,0 +1,196 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+
+package util
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+const (
+	// WatchNamespaceSelectorEnvVar : label selector of the namespaces managed by an operator watching the whole cluster
+	WatchNamespaceSelectorEnvVar = "WATCH_NAMESPACE_SELECTOR"
+
+	// TenantDefaultsAnnotation : annotation marking the config map that holds the defaults of a tenant namespace
+	TenantDefaultsAnnotation = "codewind.eclipse.org/tenant-defaults"
+)
+
+// TenantDefaults : defaults of a tenant namespace, read from its annotated config map. Fields left empty fall back
+// to the operator config map
+type TenantDefaults struct {
+	IngressDomain      string
+	StorageClassName   string
+	KeycloakDeployment string
+	KeycloakNamespace  string
+}
+
+// GetWatchNamespaces : returns the namespaces listed in WATCH_NAMESPACE, separated by commas. An empty list means
+// the operator watches the whole cluster
+func GetWatchNamespaces() ([]string, error) {
+	watchNamespace, err := k8sutil.GetWatchNamespace()
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []string{}
+	seen := map[string]bool{}
+	for _, namespace := range strings.Split(watchNamespace, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, nil
+}
+
+// GetWatchNamespaceSelector : returns the selector set in WATCH_NAMESPACE_SELECTOR, or nil when every namespace is
+// managed
+func GetWatchNamespaceSelector() (labels.Selector, error) {
+	value := strings.TrimSpace(os.Getenv(WatchNamespaceSelectorEnvVar))
+	if value == "" {
+		return nil, nil
+	}
+	return labels.Parse(value)
+}
+
+// NamespaceWatched : returns true when the labels of a namespace match the watch namespace selector, or when no
+// selector is set
+func NamespaceWatched(c client.Client, namespace string) (bool, error) {
+	selector, err := GetWatchNamespaceSelector()
+	if err != nil || selector == nil {
+		return selector == nil, err
+	}
+	namespaceDef := &corev1.Namespace{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: namespace}, namespaceDef)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespaceDef.Labels)), nil
+}
+
+// GetTenantDefaults : reads the defaults of a namespace from its config map annotated with
+// codewind.eclipse.org/tenant-defaults=true. Returns empty defaults when the namespace has no such config map
+func GetTenantDefaults(c client.Client, namespace string) (TenantDefaults, error) {
+	configMaps := &corev1.ConfigMapList{}
+	err := c.List(context.TODO(), configMaps, client.InNamespace(namespace))
+	if err != nil {
+		return TenantDefaults{}, err
+	}
+	names := []string{}
+	var tenantConfigMap *corev1.ConfigMap
+	for i := range configMaps.Items {
+		if IsTenantDefaults(&configMaps.Items[i]) {
+			tenantConfigMap = &configMaps.Items[i]
+			names = append(names, tenantConfigMap.Name)
+		}
+	}
+	if len(names) > 1 {
+		sort.Strings(names)
+		return TenantDefaults{}, errors.New("Namespace " + namespace + " has more than one tenant defaults config map: " + strings.Join(names, ", "))
+	}
+	if tenantConfigMap == nil {
+		return TenantDefaults{}, nil
+	}
+	return TenantDefaults{
+		IngressDomain:      tenantConfigMap.Data["ingressDomain"],
+		StorageClassName:   tenantConfigMap.Data["storageClassName"],
+		KeycloakDeployment: tenantConfigMap.Data["keycloakDeployment"],
+		KeycloakNamespace:  tenantConfigMap.Data["keycloakNamespace"],
+	}, nil
+}
+
+// KeycloakReference : namespace and name of the operator managed Keycloak of a Codewind instance, from its spec,
+// else from the defaults of its namespace. The namespace defaults to the namespace of the instance so that a
+// Keycloak of another tenant with the same name is never used
+func KeycloakReference(codewind *codewindv1alpha1.Codewind, tenantDefaults TenantDefaults) types.NamespacedName {
+	name := codewind.Spec.KeycloakDeployment
+	namespace := codewind.Spec.KeycloakNamespace
+	if name == "" {
+		name = tenantDefaults.KeycloakDeployment
+		namespace = ValueOrDefault(namespace, tenantDefaults.KeycloakNamespace)
+	}
+	return types.NamespacedName{Name: name, Namespace: ValueOrDefault(namespace, codewind.Namespace)}
+}
+
+// IsTenantDefaults : returns true when a config map holds the defaults of its namespace
+func IsTenantDefaults(configMap *corev1.ConfigMap) bool {
+	return strings.EqualFold(configMap.GetAnnotations()[TenantDefaultsAnnotation], "true")
+}
+
+// TenantDefaultsChanged : passes the events of config maps that hold, or used to hold, the defaults of a namespace
+var TenantDefaultsChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		configMap, ok := e.Object.(*corev1.ConfigMap)
+		return ok && IsTenantDefaults(configMap)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldConfigMap, ok := e.ObjectOld.(*corev1.ConfigMap)
+		if !ok {
+			return false
+		}
+		newConfigMap, ok := e.ObjectNew.(*corev1.ConfigMap)
+		return ok && (IsTenantDefaults(oldConfigMap) || IsTenantDefaults(newConfigMap))
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		configMap, ok := e.Object.(*corev1.ConfigMap)
+		return ok && IsTenantDefaults(configMap)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// RequestsForTenantDefaults : maps the tenant defaults config map of a namespace to the instances in it. The list
+// selects the kind of instance, such as a CodewindList or a KeycloakList
+func RequestsForTenantDefaults(currentClient client.Client, list runtime.Object) handler.ToRequestsFunc {
+	var log = logf.Log.WithName("controller_codewind_tenancyutils.go")
+	return func(object handler.MapObject) []reconcile.Request {
+		instances := list.DeepCopyObject()
+		err := currentClient.List(context.TODO(), instances, client.InNamespace(object.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "Unable to list the instances of the namespace", "Namespace", object.Meta.GetNamespace())
+			return nil
+		}
+		items, err := meta.ExtractList(instances)
+		if err != nil {
+			log.Error(err, "Unable to read the instances of the namespace", "Namespace", object.Meta.GetNamespace())
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: accessor.GetName(), Namespace: accessor.GetNamespace()}})
+		}
+		return requests
+	}
+}
@@ -15,18 +15,34 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"math/big"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
-// GenerateCertificate : generates a key and certificate
+// certificateLifetime : validity of generated certificates
+const certificateLifetime = time.Hour * 24 * 730
+
+// caLifetime : validity of the generated operator CA
+const caLifetime = time.Hour * 24 * 3650
+
+// CertificateAuthority : CA used to sign the certificates generated by the operator
+type CertificateAuthority struct {
+	Certificate    *x509.Certificate
+	PrivateKey     *rsa.PrivateKey
+	CertificatePEM string
+}
+
+// GenerateCertificate : generates a key and certificate, signed by the CA when one is given else self-signed
 // returns ServerKey ServerCert, error
-func GenerateCertificate(dnsName string, certTitle string) (string, string, error) {
+func GenerateCertificate(dnsName string, certTitle string, ca *CertificateAuthority) (string, string, error) {
 	var log = logf.Log.WithName("controller_codewind_tlsutils.go")
 
 	template := x509.Certificate{
@@ -35,7 +51,7 @@ func GenerateCertificate(dnsName string, certTitle string) (string, string, erro
 			Organization: []string{certTitle},
 		},
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().Add(time.Hour * 24 * 730),
+		NotAfter:              time.Now().Add(certificateLifetime),
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
@@ -48,8 +64,20 @@ func GenerateCertificate(dnsName string, certTitle string) (string, string, erro
 		return "", "", err
 	}
 
+	parent := &template
+	var signingKey interface{} = privateKey
+	if ca != nil {
+		// A certificate cannot outlive the CA that signs it
+		if template.NotAfter.After(ca.Certificate.NotAfter) {
+			template.NotAfter = ca.Certificate.NotAfter
+		}
+		template.AuthorityKeyId = ca.Certificate.SubjectKeyId
+		parent = ca.Certificate
+		signingKey = ca.PrivateKey
+	}
+
 	log.Info("Creating " + dnsName + " server certificate")
-	certDerBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, publicKey(privateKey), privateKey)
+	certDerBytes, err := x509.CreateCertificate(rand.Reader, &template, parent, publicKey(privateKey), signingKey)
 	if err != nil {
 		return "", "", err
 	}
@@ -65,6 +93,150 @@ func GenerateCertificate(dnsName string, certTitle string) (string, string, erro
 	return pemPrivateKey, pemPublicCert, nil
 }
 
+// GenerateCertificateAuthority : generates the key and self-signed certificate of a CA
+// returns CAKey CACert, error
+func GenerateCertificateAuthority(caTitle string) (string, string, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", "", err
+	}
+	subjectKeyID := sha1.Sum(x509.MarshalPKCS1PublicKey(&privateKey.PublicKey))
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano() / 1000000),
+		Subject: pkix.Name{
+			CommonName:   caTitle,
+			Organization: []string{caTitle},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(caLifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          subjectKeyID[:],
+	}
+	certDerBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, publicKey(privateKey), privateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	out := &bytes.Buffer{}
+	pem.Encode(out, &pem.Block{Type: "CERTIFICATE", Bytes: certDerBytes})
+	pemCACert := out.String()
+	out.Reset()
+	pem.Encode(out, pemBlockForKey(privateKey))
+	pemCAKey := out.String()
+
+	return pemCAKey, pemCACert, nil
+}
+
+// ParseCertificateAuthority : returns the CA held in the tls.crt and tls.key fields of a TLS secret
+func ParseCertificateAuthority(secret *corev1.Secret) (*CertificateAuthority, error) {
+	certificate, err := parseCertificatePEM(secret.Data["tls.crt"])
+	if err != nil {
+		return nil, errors.New("secret " + secret.Name + " does not hold a PEM certificate")
+	}
+	if !certificate.IsCA {
+		return nil, errors.New("the certificate in secret " + secret.Name + " is not a CA")
+	}
+	block, _ := pem.Decode(secret.Data["tls.key"])
+	if block == nil {
+		return nil, errors.New("secret " + secret.Name + " does not hold a PEM private key")
+	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateAuthority{
+		Certificate:    certificate,
+		PrivateKey:     privateKey,
+		CertificatePEM: string(secret.Data["tls.crt"]),
+	}, nil
+}
+
+// CertificateIssuedBy : returns true when the certificate held in the tls.crt field of a TLS secret is signed by
+// the CA
+func CertificateIssuedBy(secret *corev1.Secret, ca *CertificateAuthority) bool {
+	pemCert := secret.Data["tls.crt"]
+	if len(pemCert) == 0 {
+		pemCert = []byte(secret.StringData["tls.crt"])
+	}
+	certificate, err := parseCertificatePEM(pemCert)
+	if err != nil {
+		return false
+	}
+	return certificate.CheckSignatureFrom(ca.Certificate) == nil
+}
+
+// CertificateNotAfter : returns the expiry time of the certificate held in the tls.crt field of a TLS secret
+func CertificateNotAfter(secret *corev1.Secret) (time.Time, error) {
+	pemCert := secret.Data["tls.crt"]
+	if len(pemCert) == 0 {
+		pemCert = []byte(secret.StringData["tls.crt"])
+	}
+	certificate, err := parseCertificatePEM(pemCert)
+	if err != nil {
+		return time.Time{}, errors.New("secret " + secret.Name + " does not hold a PEM certificate")
+	}
+	return certificate.NotAfter, nil
+}
+
+// SecretValue : returns a field of a secret, including the string data of a secret that has just been built
+func SecretValue(secret *corev1.Secret, key string) string {
+	if value, ok := secret.Data[key]; ok {
+		return string(value)
+	}
+	return secret.StringData[key]
+}
+
+// RenewCertificateIfDue : re-issues the certificate of a TLS secret when it expires within the renewal window, cannot
+// be read, or is not signed by the CA. Returns true when the secret was changed and needs to be updated, and the
+// expiry of the certificate now in the secret
+func RenewCertificateIfDue(secret *corev1.Secret, dnsName string, certTitle string, renewalWindow time.Duration, ca *CertificateAuthority) (bool, time.Time, error) {
+	notAfter, err := CertificateNotAfter(secret)
+	if err == nil && time.Now().Add(renewalWindow).Before(notAfter) && (ca == nil || CertificateIssuedBy(secret, ca)) {
+		return false, notAfter, nil
+	}
+	pemPrivateKey, pemPublicCert, err := GenerateCertificate(dnsName, certTitle, ca)
+	if err != nil {
+		return false, notAfter, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["tls.crt"] = []byte(pemPublicCert)
+	secret.Data["tls.key"] = []byte(pemPrivateKey)
+	if ca != nil {
+		secret.Data["ca.crt"] = []byte(ca.CertificatePEM)
+	}
+	notAfter, err = CertificateNotAfter(secret)
+	return true, notAfter, err
+}
+
+// ResolveRenewalWindow : returns the certificate renewal window from the config map, else the default. Windows that
+// are not shorter than the lifetime of generated certificates are rejected
+func ResolveRenewalWindow(configMapWindow string, defaultWindow time.Duration) (time.Duration, error) {
+	if configMapWindow == "" {
+		return defaultWindow, nil
+	}
+	renewalWindow, err := time.ParseDuration(configMapWindow)
+	if err != nil {
+		return defaultWindow, err
+	}
+	// A window as long as the certificate lifetime would re-issue the certificate on every reconcile
+	if renewalWindow < 0 || renewalWindow >= certificateLifetime {
+		return defaultWindow, errors.New("certificate renewal window must be between 0 and " + certificateLifetime.String())
+	}
+	return renewalWindow, nil
+}
+
+func parseCertificatePEM(pemCert []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(pemCert)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
 func publicKey(privateKey interface{}) interface{} {
 	switch k := privateKey.(type) {
 	case *rsa.PrivateKey:
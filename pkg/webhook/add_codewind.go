@@ -0,0 +1,20 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package webhook
+
+import (
+	"github.com/eclipse/codewind-operator/pkg/webhook/codewind"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to register webhooks with the webhook server of a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, codewind.Add)
+}
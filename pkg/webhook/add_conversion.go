@@ -0,0 +1,29 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package webhook
+
+import (
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to register webhooks with the webhook server of a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, addConversion)
+}
+
+// addConversion : registers the webhook that converts Codewind resources between v1alpha1 and the v1beta1 storage
+// version
+func addConversion(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(defaults.ConvertPath, &conversion.Webhook{})
+	return nil
+}
@@ -0,0 +1,144 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// webhookCertTitle : subject of the serving certificate of the admission webhooks
+const webhookCertTitle = "Codewind Operator Webhook"
+
+// ConfigureServingCertificate : writes a serving certificate for the webhook service, signed by the operator CA, to
+// certDir and adds the CA to the webhook configurations and the Codewind CRD so that the API server trusts it. The certificate
+// is re-issued each time the operator starts
+func ConfigureServingCertificate(c client.Client, certDir string) error {
+	ca, err := util.EnsureCertificateAuthority(c, defaults.OperatorCASecretName, defaults.CABundleConfigMapName)
+	if err != nil {
+		return err
+	}
+	dnsName := defaults.WebhookServiceName + "." + util.GetOperatorNamespace() + ".svc"
+	pemPrivateKey, pemPublicCert, err := util.GenerateCertificate(dnsName, webhookCertTitle, ca)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(certDir, 0700)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(certDir, "tls.crt"), []byte(pemPublicCert), 0600)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(certDir, "tls.key"), []byte(pemPrivateKey), 0600)
+	if err != nil {
+		return err
+	}
+	return injectCABundle(c, ca)
+}
+
+// injectCABundle : sets the CA bundle of the validating and mutating webhook configurations and of the Codewind
+// conversion webhook to the operator CA. Webhooks that are not installed are skipped
+func injectCABundle(c client.Client, ca *util.CertificateAuthority) error {
+	caBundle := []byte(ca.CertificatePEM)
+	err := injectValidatingCABundle(c, caBundle)
+	if err != nil {
+		return err
+	}
+	err = injectMutatingCABundle(c, caBundle)
+	if err != nil {
+		return err
+	}
+	return injectConversionCABundle(c, caBundle)
+}
+
+// injectValidatingCABundle : sets the CA bundle of every webhook of the validating webhook configuration
+func injectValidatingCABundle(c client.Client, caBundle []byte) error {
+	validatingConfig := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: defaults.WebhookConfigurationName}, validatingConfig)
+	if err != nil && k8serr.IsNotFound(err) {
+		log.Info("The validating webhook configuration is not installed, Codewind and Keycloak resources are not validated on admission", "Name", defaults.WebhookConfigurationName)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	changed := false
+	for i := range validatingConfig.Webhooks {
+		if !bytes.Equal(validatingConfig.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			validatingConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	log.Info("Updating the CA bundle of the validating webhook configuration", "Name", defaults.WebhookConfigurationName)
+	return c.Update(context.TODO(), validatingConfig)
+}
+
+// injectMutatingCABundle : sets the CA bundle of every webhook of the mutating webhook configuration
+func injectMutatingCABundle(c client.Client, caBundle []byte) error {
+	mutatingConfig := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: defaults.WebhookConfigurationName}, mutatingConfig)
+	if err != nil && k8serr.IsNotFound(err) {
+		log.Info("The mutating webhook configuration is not installed, defaults are not added to Codewind and Keycloak resources on admission", "Name", defaults.WebhookConfigurationName)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	changed := false
+	for i := range mutatingConfig.Webhooks {
+		if !bytes.Equal(mutatingConfig.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			mutatingConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	log.Info("Updating the CA bundle of the mutating webhook configuration", "Name", defaults.WebhookConfigurationName)
+	return c.Update(context.TODO(), mutatingConfig)
+}
+
+// injectConversionCABundle : sets the CA bundle of the conversion webhook of the Codewind CRD. The OpenShift 3.11
+// CRD serves a single version and has no conversion webhook
+func injectConversionCABundle(c client.Client, caBundle []byte) error {
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: defaults.CodewindCRDName}, crd)
+	if err != nil {
+		return err
+	}
+	conversion := crd.Spec.Conversion
+	if conversion == nil || conversion.Strategy != apiextensionsv1beta1.WebhookConverter || conversion.WebhookClientConfig == nil {
+		return nil
+	}
+	if bytes.Equal(conversion.WebhookClientConfig.CABundle, caBundle) {
+		return nil
+	}
+	conversion.WebhookClientConfig.CABundle = caBundle
+	log.Info("Updating the CA bundle of the conversion webhook", "Name", defaults.CodewindCRDName)
+	return c.Update(context.TODO(), crd)
+}
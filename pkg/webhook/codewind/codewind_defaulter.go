@@ -0,0 +1,114 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package codewind
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// codewindDefaulter : stamps the defaults the controller would otherwise resolve on each reconcile onto new Codewind
+// instances, so that the effective configuration is visible in the CR
+type codewindDefaulter struct {
+	client  client.Client
+	decoder *admission.Decoder
+}
+
+// InjectClient : called by the manager to set the client used to read the operator config map and Keycloaks
+func (d *codewindDefaulter) InjectClient(c client.Client) error {
+	d.client = c
+	return nil
+}
+
+// InjectDecoder : called by the webhook to set the decoder of admission requests
+func (d *codewindDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle : fills the storage size, log level, Keycloak and workspace ID of a new Codewind instance
+func (d *codewindDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create {
+		return admission.Allowed("")
+	}
+	codewind := &codewindv1alpha1.Codewind{}
+	err := d.decoder.Decode(req, codewind)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	operatorConfig, err := util.ReadConfigMapData(d.client, defaults.OperatorConfigMapName, util.GetOperatorNamespace())
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if codewind.Spec.StorageSize == "" {
+		codewind.Spec.StorageSize = util.ValueOrDefault(operatorConfig["storageCodewindSize"], defaults.CodewindStorageSize)
+	}
+	if codewind.Spec.LogLevel == "" {
+		codewind.Spec.LogLevel = util.ValueOrDefault(operatorConfig["logLevel"], defaults.CodewindLogLevel)
+	}
+	if codewind.Spec.ExternalAuth == nil && codewind.Spec.KeycloakDeployment == "" {
+		err = d.defaultKeycloakDeployment(ctx, codewind)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+	if codewind.Spec.ExternalAuth == nil && codewind.Spec.KeycloakNamespace == "" {
+		codewind.Spec.KeycloakNamespace = codewind.Namespace
+	}
+	annotations := codewind.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if annotations[defaults.CodewindWorkspaceAnnotation] == "" {
+		annotations[defaults.CodewindWorkspaceAnnotation] = util.GenerateInstanceID()
+		codewind.SetAnnotations(annotations)
+	}
+
+	marshaledCodewind, err := json.Marshal(codewind)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledCodewind)
+}
+
+// defaultKeycloakDeployment : sets the Keycloak named by the defaults of the tenant namespace, else the only Keycloak
+// in spec.keycloakNamespace or the namespace of the instance. Leaves the name empty when neither applies and the
+// validating webhook rejects the CR
+func (d *codewindDefaulter) defaultKeycloakDeployment(ctx context.Context, codewind *codewindv1alpha1.Codewind) error {
+	tenantDefaults, err := util.GetTenantDefaults(d.client, codewind.Namespace)
+	if err != nil {
+		return err
+	}
+	if tenantDefaults.KeycloakDeployment != "" {
+		keycloakRef := util.KeycloakReference(codewind, tenantDefaults)
+		codewind.Spec.KeycloakDeployment = keycloakRef.Name
+		codewind.Spec.KeycloakNamespace = keycloakRef.Namespace
+		return nil
+	}
+	keycloaks := &codewindv1alpha1.KeycloakList{}
+	err = d.client.List(ctx, keycloaks, client.InNamespace(util.ValueOrDefault(codewind.Spec.KeycloakNamespace, codewind.Namespace)))
+	if err != nil {
+		return err
+	}
+	if len(keycloaks.Items) == 1 {
+		codewind.Spec.KeycloakDeployment = keycloaks.Items[0].Name
+	}
+	return nil
+}
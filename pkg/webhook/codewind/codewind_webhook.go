@@ -0,0 +1,171 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package codewind
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var log = logf.Log.WithName("webhook_codewind")
+
+// Add : registers the Codewind defaulting and validating webhooks with the webhook server of the manager
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(defaults.MutateCodewindPath, &admission.Webhook{Handler: &codewindDefaulter{}})
+	mgr.GetWebhookServer().Register(defaults.ValidateCodewindPath, &admission.Webhook{Handler: &codewindValidator{}})
+	return nil
+}
+
+// codewindValidator : rejects Codewind instances that reference a missing Keycloak, request invalid storage, change
+// immutable fields, or reuse the username of another instance in the same namespace
+type codewindValidator struct {
+	client  client.Client
+	decoder *admission.Decoder
+}
+
+// InjectClient : called by the manager to set the client used to look up Keycloaks and Codewind instances
+func (v *codewindValidator) InjectClient(c client.Client) error {
+	v.client = c
+	return nil
+}
+
+// InjectDecoder : called by the webhook to set the decoder of admission requests
+func (v *codewindValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle : validates the Codewind instance of a create or update request
+func (v *codewindValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	codewind := &codewindv1alpha1.Codewind{}
+	err := v.decoder.Decode(req, codewind)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	switch req.Operation {
+	case admissionv1beta1.Create:
+		return v.validateCreate(ctx, codewind)
+	case admissionv1beta1.Update:
+		oldCodewind := &codewindv1alpha1.Codewind{}
+		err = v.decoder.DecodeRaw(req.OldObject, oldCodewind)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Metadata changes, such as removing a finalizer, and updates of a CR being deleted are never blocked
+		if codewind.DeletionTimestamp != nil || equality.Semantic.DeepEqual(codewind.Spec, oldCodewind.Spec) {
+			return admission.Allowed("")
+		}
+		return validateUpdate(codewind, oldCodewind)
+	}
+	return admission.Allowed("")
+}
+
+// validateCreate : checks the storage size, the Keycloak reference and that the username is not already bound to
+// another instance in the namespace
+func (v *codewindValidator) validateCreate(ctx context.Context, codewind *codewindv1alpha1.Codewind) admission.Response {
+	if response, ok := validateStorageSize(codewind.Spec.StorageSize); !ok {
+		return response
+	}
+	if err := util.ValidateStorageSpec(codewind.Spec.Storage); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	// An external Keycloak is checked by the controller when the instance registers with it
+	if codewind.Spec.ExternalAuth == nil {
+		tenantDefaults := util.TenantDefaults{}
+		if codewind.Spec.KeycloakDeployment == "" {
+			var err error
+			tenantDefaults, err = util.GetTenantDefaults(v.client, codewind.Namespace)
+			if err != nil {
+				return admission.Errored(http.StatusInternalServerError, err)
+			}
+		}
+		keycloakRef := util.KeycloakReference(codewind, tenantDefaults)
+		if keycloakRef.Name == "" {
+			return admission.Denied("spec.keycloakDeployment must be set unless spec.externalAuth is set, the namespace has tenant defaults naming a Keycloak, or the namespace has a single Keycloak")
+		}
+		keycloak := &codewindv1alpha1.Keycloak{}
+		err := v.client.Get(ctx, keycloakRef, keycloak)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				return admission.Denied(fmt.Sprintf("Keycloak '%s' does not exist in namespace '%s'", keycloakRef.Name, keycloakRef.Namespace))
+			}
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	// Keycloak usernames are case insensitive
+	codewinds := &codewindv1alpha1.CodewindList{}
+	err := v.client.List(ctx, codewinds, client.InNamespace(codewind.Namespace))
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	for _, existing := range codewinds.Items {
+		if existing.Name != codewind.Name && strings.EqualFold(existing.Spec.Username, codewind.Spec.Username) {
+			log.Info("Rejecting a Codewind instance with a username already in use", "Namespace", codewind.Namespace, "Name", codewind.Name, "Existing", existing.Name)
+			return admission.Denied(fmt.Sprintf("username '%s' is already assigned to Codewind instance '%s' in namespace '%s'", codewind.Spec.Username, existing.Name, codewind.Namespace))
+		}
+	}
+	return admission.Allowed("")
+}
+
+// validateUpdate : rejects changes to the fields an instance is registered with Keycloak under, and storage changes
+// that cannot be applied to the existing PVC
+func validateUpdate(codewind *codewindv1alpha1.Codewind, oldCodewind *codewindv1alpha1.Codewind) admission.Response {
+	if codewind.Spec.Username != oldCodewind.Spec.Username {
+		return admission.Denied("spec.username cannot be changed after the Codewind instance is created")
+	}
+	if codewind.Spec.KeycloakDeployment != oldCodewind.Spec.KeycloakDeployment {
+		return admission.Denied("spec.keycloakDeployment cannot be changed after the Codewind instance is created")
+	}
+	if util.ValueOrDefault(codewind.Spec.KeycloakNamespace, codewind.Namespace) != util.ValueOrDefault(oldCodewind.Spec.KeycloakNamespace, oldCodewind.Namespace) {
+		return admission.Denied("spec.keycloakNamespace cannot be changed after the Codewind instance is created")
+	}
+	if codewind.Spec.StorageSize != oldCodewind.Spec.StorageSize {
+		if response, ok := validateStorageSize(codewind.Spec.StorageSize); !ok {
+			return response
+		}
+	}
+	if err := util.ValidateStorageSpec(codewind.Spec.Storage); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := util.ValidateStorageUpdate(oldCodewind.Spec.Storage, codewind.Spec.Storage, oldCodewind.Spec.StorageSize, codewind.Spec.StorageSize); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// validateStorageSize : returns a denial when a storage size is set and is not a quantity
+func validateStorageSize(storageSize string) (admission.Response, bool) {
+	if storageSize == "" {
+		return admission.Response{}, true
+	}
+	_, err := resource.ParseQuantity(storageSize)
+	if err != nil {
+		return admission.Denied(fmt.Sprintf("spec.storageSize '%s' is not a valid quantity: %v", storageSize, err)), false
+	}
+	return admission.Response{}, true
+}
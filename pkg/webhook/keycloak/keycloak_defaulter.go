@@ -0,0 +1,69 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// keycloakDefaulter : stamps the storage size of the operator config map onto new Keycloaks
+type keycloakDefaulter struct {
+	client  client.Client
+	decoder *admission.Decoder
+}
+
+// InjectClient : called by the manager to set the client used to read the operator config map
+func (d *keycloakDefaulter) InjectClient(c client.Client) error {
+	d.client = c
+	return nil
+}
+
+// InjectDecoder : called by the webhook to set the decoder of admission requests
+func (d *keycloakDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle : fills the storage size of a new Keycloak
+func (d *keycloakDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create {
+		return admission.Allowed("")
+	}
+	keycloak := &codewindv1alpha1.Keycloak{}
+	err := d.decoder.Decode(req, keycloak)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if keycloak.Spec.StorageSize != "" {
+		return admission.Allowed("")
+	}
+
+	operatorConfig, err := util.ReadConfigMapData(d.client, defaults.OperatorConfigMapName, util.GetOperatorNamespace())
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	keycloak.Spec.StorageSize = util.ValueOrDefault(operatorConfig["storageKeycloakSize"], defaults.KeycloakStorageSize)
+
+	marshaledKeycloak, err := json.Marshal(keycloak)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledKeycloak)
+}
@@ -0,0 +1,89 @@
+/*******************************************************************************
+ * Copyright (c) 2020 IBM Corporation and others.
+ * All rights reserved. This program and the accompanying materials
+ * are made available under the terms of the Eclipse Public License v2.0
+ * which accompanies this distribution, and is available at
+ * http://www.eclipse.org/legal/epl-v20.html
+ *
+ * Contributors:
+ *     IBM Corporation - initial API and implementation
+ *******************************************************************************/
+package keycloak
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	codewindv1alpha1 "github.com/eclipse/codewind-operator/pkg/apis/codewind/v1alpha1"
+	defaults "github.com/eclipse/codewind-operator/pkg/controller/defaults"
+	util "github.com/eclipse/codewind-operator/pkg/util"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Add : registers the Keycloak defaulting and validating webhooks with the webhook server of the manager
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(defaults.MutateKeycloakPath, &admission.Webhook{Handler: &keycloakDefaulter{}})
+	mgr.GetWebhookServer().Register(defaults.ValidateKeycloakPath, &admission.Webhook{Handler: &keycloakValidator{}})
+	return nil
+}
+
+// keycloakValidator : rejects Keycloaks that request invalid storage, or storage changes that cannot be applied to
+// the existing PVC
+type keycloakValidator struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder : called by the webhook to set the decoder of admission requests
+func (v *keycloakValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle : validates the Keycloak of a create or update request
+func (v *keycloakValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	keycloak := &codewindv1alpha1.Keycloak{}
+	err := v.decoder.Decode(req, keycloak)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	oldKeycloak := &codewindv1alpha1.Keycloak{}
+	if req.Operation == admissionv1beta1.Update {
+		err = v.decoder.DecodeRaw(req.OldObject, oldKeycloak)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Metadata changes, such as removing a finalizer, and updates of a CR being deleted are never blocked
+		if keycloak.DeletionTimestamp != nil || equality.Semantic.DeepEqual(keycloak.Spec, oldKeycloak.Spec) {
+			return admission.Allowed("")
+		}
+	}
+	if keycloak.Spec.StorageSize != "" {
+		_, err = resource.ParseQuantity(keycloak.Spec.StorageSize)
+		if err != nil {
+			return admission.Denied(fmt.Sprintf("spec.storageSize '%s' is not a valid quantity: %v", keycloak.Spec.StorageSize, err))
+		}
+	}
+	err = util.ValidateStorageSpec(keycloak.Spec.Storage)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	// Replicas share their sessions through the database, the embedded H2 database cannot be shared
+	if keycloak.Spec.Replicas != nil && *keycloak.Spec.Replicas > 1 {
+		database := keycloak.Spec.Database
+		if database == nil || database.Vendor == "" || database.Vendor == codewindv1alpha1.KeycloakDatabaseH2 {
+			return admission.Denied(fmt.Sprintf("spec.replicas %d requires spec.database to name an external database, the embedded H2 database supports a single replica", *keycloak.Spec.Replicas))
+		}
+	}
+	if req.Operation == admissionv1beta1.Update {
+		err = util.ValidateStorageUpdate(oldKeycloak.Spec.Storage, keycloak.Spec.Storage, oldKeycloak.Spec.StorageSize, keycloak.Spec.StorageSize)
+		if err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+	return admission.Allowed("")
+}